@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/decisiveai/mdai-cli/internal/backup"
+	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func NewBackupCommand() *cobra.Command {
+	flags := backupFlags{}
+	cmd := &cobra.Command{
+		GroupID: "installation",
+		Use:     "backup [-f FILE]",
+		Short:   "back up MDAI configuration",
+		Long:    "back up the MyDecisiveEngine spec, including telemetry filters and otel collector configuration, and the mdai-cluster helm values",
+		Example: `  mdai backup                  # back up to mdai-backup-<timestamp>.yaml
+  mdai backup -f mdai-prod.yaml # back up to mdai-prod.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return mdaiBackup(cmd.Context(), flags.file)
+		},
+	}
+	cmd.Flags().StringVarP(&flags.file, "file", "f", "", "file to write the backup to")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func mdaiBackup(ctx context.Context, filename string) error {
+	if filename == "" {
+		filename = defaultBackupFilename()
+	}
+
+	helmclient := mdaihelm.NewClient(mdaihelm.WithContext(ctx))
+	rel, err := helmclient.Release("mdai-cluster")
+	if err != nil {
+		return fmt.Errorf("failed to get mdai-cluster release: %w", err)
+	}
+
+	helper, err := kubehelper.New(kubehelper.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	manifest, err := helper.GetOperatorManifest(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get mdai operator: %w", err)
+	}
+
+	b := backup.Backup{
+		FormatVersion: backup.FormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		CLIVersion:    Version,
+		Chart: backup.Chart{
+			Name:    rel.Chart.Metadata.Name,
+			Version: rel.Chart.Metadata.Version,
+			Values:  rel.Config,
+		},
+	}
+	if err := yaml.Unmarshal(manifest, &b.Engine); err != nil {
+		return fmt.Errorf("failed to unmarshal mdai operator: %w", err)
+	}
+	if err := b.Save(filename); err != nil {
+		return err
+	}
+
+	fmt.Printf("engine %s (%d filters) and %s %s values backed up to %s\n",
+		PurpleStyle.Render(b.EngineName()),
+		b.Filters(),
+		b.Chart.Name,
+		b.Chart.Version,
+		PurpleStyle.Render(filename),
+	)
+	return nil
+}
+
+func defaultBackupFilename() string {
+	return "mdai-backup-" + time.Now().Format("20060102-150405") + ".yaml"
+}
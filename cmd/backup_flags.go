@@ -0,0 +1,5 @@
+package cmd
+
+type backupFlags struct {
+	file string
+}
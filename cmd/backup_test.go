@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBackupCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "backup command with args",
+			args: []string{"backup", "mdai.yaml"},
+			err:  errors.New(`unknown command "mdai.yaml" for "mdai backup"`),
+		},
+	}
+
+	errTests.Run(t)
+}
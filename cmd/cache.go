@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/decisiveai/mdai-cli/internal/cache"
+	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
+	"github.com/spf13/cobra"
+)
+
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		GroupID: "installation",
+		Use:     "cache",
+		Short:   "manage the local chart cache",
+		Long:    "list and prune helm charts cached under " + cache.Dir() + ", which are reused by install when offline",
+		Example: `  mdai cache list                    # list cached charts
+  mdai cache prune                   # remove charts this version of mdai no longer installs
+  mdai cache prune --older-than 720h # also remove charts cached more than 30 days ago
+  mdai cache prune --all             # empty the cache`,
+	}
+
+	cmd.AddCommand(
+		newCacheListCommand(),
+		newCachePruneCommand(),
+	)
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newCacheListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list cached charts",
+		Long:  "list cached charts with their digests",
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			entries, err := cache.List()
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Println("chart cache is empty")
+				return nil
+			}
+			wanted := mdaihelm.ChartVersions()
+			t := styledTable().Headers(cacheHeaders()...)
+			for _, entry := range entries {
+				inUse := NoDataString
+				if wanted[entry.Name] == entry.Version {
+					inUse = EnabledString
+				}
+				t.Row(entry.Name, entry.Version, entry.Digest, entry.Source, formatBytes(entry.Size), entry.CachedAt.Format(time.RFC3339), inUse)
+			}
+			fmt.Println(t)
+			fmt.Printf("cache: %s\n", PurpleStyle.Render(cache.Dir()))
+			return nil
+		},
+	}
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newCachePruneCommand() *cobra.Command {
+	flags := cachePruneFlags{}
+	cmd := &cobra.Command{
+		Use:   "prune [--all] [--older-than DURATION]",
+		Short: "remove cached charts",
+		Long:  "remove cached charts that this version of mdai does not install, optionally also those older than a duration, or all of them",
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+		},
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if flags.olderThan < 0 {
+				return fmt.Errorf("invalid older-than: %s", flags.olderThan)
+			}
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			entries, err := cache.List()
+			if err != nil {
+				return err
+			}
+			wanted := mdaihelm.ChartVersions()
+			var removed []string
+			for _, entry := range entries {
+				stale := wanted[entry.Name] != entry.Version
+				expired := flags.olderThan > 0 && time.Since(entry.CachedAt) > flags.olderThan
+				if !flags.all && !stale && !expired {
+					continue
+				}
+				if err := entry.Remove(); err != nil {
+					return err
+				}
+				removed = append(removed, entry.Name+"-"+entry.Version+" ("+entry.Digest+")")
+			}
+			if len(removed) == 0 {
+				fmt.Println("nothing to prune")
+				return nil
+			}
+			fmt.Printf("removed %d cached chart(s):\n  %s\n", len(removed), strings.Join(removed, "\n  "))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&flags.all, "all", false, "remove all cached charts")
+	cmd.Flags().DurationVar(&flags.olderThan, "older-than", 0, "also remove charts cached longer ago than this")
+
+	cmd.MarkFlagsMutuallyExclusive("all", "older-than")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,8 @@
+package cmd
+
+import "time"
+
+type cachePruneFlags struct {
+	all       bool
+	olderThan time.Duration
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCacheCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "cache list with args",
+			args: []string{"cache", "list", "mdai-cluster"},
+			err:  errors.New(`unknown command "mdai-cluster" for "mdai cache list"`),
+		},
+		{
+			name: "cache prune with negative --older-than",
+			args: []string{"cache", "prune", "--older-than", "-1h"},
+			err:  errors.New("invalid older-than: -1h0m0s"),
+		},
+		{
+			name: "cache prune with both --all and --older-than",
+			args: []string{"cache", "prune", "--all", "--older-than", "1h"},
+			err:  errors.New(`if any flags in the group [all older-than] are set none of the others can be; [all older-than] were all set`),
+		},
+	}
+
+	errTests.Run(t)
+}
@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/decisiveai/mdai-cli/internal/config"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/util/homedir"
+)
+
+// createsProfile marks commands that may select a profile which does not exist yet.
+const createsProfile = "mdai.ai/creates-profile"
+
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceProfile = "profile"
+	sourceDefault = "default"
+)
+
+type setting struct {
+	value  string
+	source string
+}
+
+// A flag wins over the environment, which wins over the selected profile.
+func settingEnvs() map[string][]string {
+	return map[string][]string{
+		"profile":     {"MDAI_PROFILE"},
+		"kubeconfig":  {"MDAI_KUBECONFIG", "KUBECONFIG"},
+		"kubecontext": {"MDAI_KUBECONTEXT", "KUBECONTEXT"},
+		"namespace":   {"MDAI_NAMESPACE"},
+		"engine":      {"MDAI_ENGINE"},
+		"collector":   {"MDAI_COLLECTOR"},
+		"output":      {"MDAI_OUTPUT"},
+		"chart-repo":  {"MDAI_CHART_REPO"},
+	}
+}
+
+func bindSettingEnvs() {
+	for key, envs := range settingEnvs() {
+		_ = viper.BindEnv(append([]string{key}, envs...)...)
+	}
+}
+
+func loadSettings(cmd *cobra.Command) (string, map[string]setting, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", nil, err
+	}
+	name := viper.GetString("profile")
+	_, creates := cmd.Annotations[createsProfile]
+	switch {
+	case name == "" && cfg.CurrentProfile != "":
+		name = cfg.CurrentProfile
+	case name == "":
+		name = config.DefaultProfile
+	case !cfg.HasProfile(name) && !creates:
+		return "", nil, fmt.Errorf(`profile "%s" does not exist in %s`, name, config.Path())
+	}
+	profile := cfg.Profile(name)
+
+	settings := make(map[string]setting, len(config.Keys()))
+	for _, key := range config.Keys() {
+		settings[key] = resolveSetting(cmd, profile, key)
+	}
+	if kubeconfig := settings["kubeconfig"]; kubeconfig.value == "" {
+		if home := homedir.HomeDir(); home != "" {
+			settings["kubeconfig"] = setting{filepath.Join(home, ".kube", "config"), sourceDefault}
+		}
+	} else if rest, ok := strings.CutPrefix(kubeconfig.value, "~/"); ok {
+		kubeconfig.value = filepath.Join(homedir.HomeDir(), rest)
+		settings["kubeconfig"] = kubeconfig
+	}
+	if settings["namespace"].value == "" {
+		settings["namespace"] = setting{config.DefaultNamespace, sourceDefault}
+	}
+	return name, settings, nil
+}
+
+func resolveSetting(cmd *cobra.Command, profile *config.Profile, key string) setting {
+	if flag := cmd.Flags().Lookup(key); flag != nil && flag.Changed {
+		return setting{flag.Value.String(), sourceFlag}
+	}
+	if value := viper.GetString(key); value != "" {
+		return setting{value, sourceEnv}
+	}
+	if value, _ := profile.Get(key); value != "" {
+		return setting{value, sourceProfile}
+	}
+	return setting{}
+}
+
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		GroupID: "configuration",
+		Use:     "config",
+		Short:   "manage mdai cli profiles",
+		Long: `manage the named profiles stored in ` + config.Path() + `
+
+a profile bundles the kubeconfig, context, namespace, engine, collector, output format and chart source
+mdai uses; a flag wins over its MDAI_* environment variable, which wins over the selected profile`,
+		Example: `  mdai config list                                      # list profiles
+  mdai config use staging                               # make staging the current profile
+  mdai config set --profile staging kubecontext kind-mdai # create or change a profile
+  mdai config view                                      # show the settings in effect and where they come from`,
+	}
+
+	cmd.AddCommand(
+		newConfigListCommand(),
+		newConfigSetCommand(),
+		newConfigUseCommand(),
+		newConfigViewCommand(),
+	)
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newConfigListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list profiles",
+		Long:  "list profiles, the current one is marked",
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if len(cfg.Profiles) == 0 {
+				fmt.Printf("no profiles in %s\n", config.Path())
+				return nil
+			}
+			t := styledTable().Headers(configProfileHeaders()...)
+			for _, name := range cfg.ProfileNames() {
+				current := ""
+				if name == cfg.CurrentProfile {
+					current = EnabledString
+				}
+				profile := cfg.Profile(name)
+				t.Row(current, name, orNoData(profile.Kubecontext), orNoData(profile.Namespace), orNoData(profile.Engine), orNoData(profile.Collector))
+			}
+			fmt.Println(t)
+			return nil
+		},
+	}
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newConfigUseCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use PROFILE",
+		Short: "switch the current profile",
+		Long:  "make PROFILE the profile used when --profile and MDAI_PROFILE are not set",
+		Args:  cobra.ExactArgs(1),
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+		},
+		ValidArgsFunction: profileCompletionFunc,
+		RunE: func(_ *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if !cfg.HasProfile(args[0]) {
+				return fmt.Errorf(`profile "%s" does not exist, create it with mdai config set --profile %s`, args[0], args[0])
+			}
+			cfg.CurrentProfile = args[0]
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+			fmt.Printf("switched to profile %s\n", PurpleStyle.Render(args[0]))
+			return nil
+		},
+	}
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newConfigSetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "change a profile setting",
+		Long:  "change a setting of the selected profile, creating the profile if needed; an empty VALUE removes the setting\n\nkeys: " + strings.Join(config.Keys(), ", "),
+		Args:  cobra.ExactArgs(2), //nolint: mnd
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+			createsProfile:     "",
+		},
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return config.Keys(), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if !slices.Contains(config.Keys(), args[0]) {
+				return fmt.Errorf("invalid key: %s, expected one of [%s]", args[0], strings.Join(config.Keys(), ", "))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			name := cmd.Context().Value(mdaitypes.Profile{}).(string)
+			if err := cfg.Profile(name).Set(args[0], args[1]); err != nil {
+				return err
+			}
+			if cfg.CurrentProfile == "" {
+				cfg.CurrentProfile = name
+			}
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+			fmt.Printf("profile %s: %s set to %s\n", PurpleStyle.Render(name), args[0], PurpleStyle.Render(orNoData(args[1])))
+			return nil
+		},
+	}
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newConfigViewCommand() *cobra.Command {
+	flags := configViewFlags{}
+	cmd := &cobra.Command{
+		Use:   "view [--raw]",
+		Short: "show the settings in effect",
+		Long:  "show each setting in effect and whether it comes from a flag, the environment, the profile or a default",
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if flags.raw {
+				cfg, err := config.Load()
+				if err != nil {
+					return err
+				}
+				data, err := yaml.Marshal(cfg)
+				if err != nil {
+					return fmt.Errorf("failed to marshal config: %w", err)
+				}
+				fmt.Print(string(data))
+				return nil
+			}
+			name, settings, err := loadSettings(cmd)
+			if err != nil {
+				return err
+			}
+			t := styledTable().Headers(configViewHeaders()...)
+			for _, key := range config.Keys() {
+				t.Row(key, orNoData(settings[key].value), orNoData(settings[key].source))
+			}
+			fmt.Printf("profile: %s\n", PurpleStyle.Render(name))
+			fmt.Println(t)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&flags.raw, "raw", false, "print "+config.Path()+" as stored")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func profileCompletionFunc(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return cfg.ProfileNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func orNoData(s string) string {
+	if s == "" {
+		return NoDataString
+	}
+	return s
+}
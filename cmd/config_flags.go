@@ -0,0 +1,5 @@
+package cmd
+
+type configViewFlags struct {
+	raw bool
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decisiveai/mdai-cli/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "config use command without profile",
+			args: []string{"config", "use"},
+			err:  errors.New("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "config use command with unknown profile",
+			args: []string{"config", "use", "does-not-exist"},
+			err:  errors.New(`profile "does-not-exist" does not exist, create it with mdai config set --profile does-not-exist`),
+		},
+		{
+			name: "config set command without value",
+			args: []string{"config", "set", "namespace"},
+			err:  errors.New("accepts 2 arg(s), received 1"),
+		},
+		{
+			name: "config set command with unknown key",
+			args: []string{"config", "set", "--profile", "test", "color", "purple"},
+			err:  errors.New("invalid key: color, expected one of [kubeconfig, kubecontext, namespace, engine, collector, output, chart-repo]"),
+		},
+		{
+			name: "config view command with unknown profile",
+			args: []string{"config", "view", "--profile", "does-not-exist"},
+			err:  errors.New(`profile "does-not-exist" does not exist in ` + config.Path()),
+		},
+	}
+
+	errTests.Run(t)
+}
+
+func writeTestConfig(t *testing.T, data string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(config.Path()), 0o700))
+	require.NoError(t, os.WriteFile(config.Path(), []byte(data), 0o600))
+	t.Cleanup(func() { _ = os.RemoveAll(filepath.Dir(config.Path())) })
+}
+
+func TestLoadSettings(t *testing.T) {
+	const profiles = `current-profile: staging
+profiles:
+  staging:
+    namespace: mdai-staging
+    kubecontext: kind-staging
+  prod:
+    namespace: mdai-prod
+`
+	tests := []struct {
+		name    string
+		config  string
+		env     map[string]string
+		args    []string
+		profile string
+		want    map[string]setting
+	}{
+		{
+			name:    "defaults",
+			profile: config.DefaultProfile,
+			want: map[string]setting{
+				"namespace":   {config.DefaultNamespace, sourceDefault},
+				"kubecontext": {},
+				"kubeconfig":  {filepath.Join(os.Getenv("HOME"), ".kube", "config"), sourceDefault},
+			},
+		},
+		{
+			name:    "current profile",
+			config:  profiles,
+			profile: "staging",
+			want: map[string]setting{
+				"namespace":   {"mdai-staging", sourceProfile},
+				"kubecontext": {"kind-staging", sourceProfile},
+			},
+		},
+		{
+			name:    "profile flag",
+			config:  profiles,
+			args:    []string{"--profile", "prod"},
+			profile: "prod",
+			want: map[string]setting{
+				"namespace":   {"mdai-prod", sourceProfile},
+				"kubecontext": {},
+			},
+		},
+		{
+			name:    "profile env",
+			config:  profiles,
+			env:     map[string]string{"MDAI_PROFILE": "prod"},
+			profile: "prod",
+			want: map[string]setting{
+				"namespace": {"mdai-prod", sourceProfile},
+			},
+		},
+		{
+			name:    "env over profile",
+			config:  profiles,
+			env:     map[string]string{"MDAI_NAMESPACE": "mdai-env", "KUBECONTEXT": "kind-env"},
+			profile: "staging",
+			want: map[string]setting{
+				"namespace":   {"mdai-env", sourceEnv},
+				"kubecontext": {"kind-env", sourceEnv},
+			},
+		},
+		{
+			name:    "mdai env over generic env",
+			env:     map[string]string{"MDAI_KUBECONTEXT": "kind-mdai", "KUBECONTEXT": "kind-env"},
+			profile: config.DefaultProfile,
+			want: map[string]setting{
+				"kubecontext": {"kind-mdai", sourceEnv},
+			},
+		},
+		{
+			name:    "flag over env",
+			config:  profiles,
+			env:     map[string]string{"MDAI_NAMESPACE": "mdai-env"},
+			args:    []string{"--namespace", "mdai-flag", "--kubeconfig", "~/kind.yaml"},
+			profile: "staging",
+			want: map[string]setting{
+				"namespace":  {"mdai-flag", sourceFlag},
+				"kubeconfig": {filepath.Join(os.Getenv("HOME"), "kind.yaml"), sourceFlag},
+			},
+		},
+		{
+			name:    "legacy namespace",
+			config:  "namespace: mdai-legacy\n",
+			profile: config.DefaultProfile,
+			want: map[string]setting{
+				"namespace": {"mdai-legacy", sourceProfile},
+			},
+		},
+		{
+			name:    "legacy namespace under a profile namespace",
+			config:  "namespace: mdai-legacy\n" + profiles,
+			profile: "staging",
+			want: map[string]setting{
+				"namespace": {"mdai-staging", sourceProfile},
+			},
+		},
+		{
+			name:    "legacy namespace under an env namespace",
+			config:  "namespace: mdai-legacy\n",
+			env:     map[string]string{"MDAI_NAMESPACE": "mdai-env"},
+			profile: config.DefaultProfile,
+			want: map[string]setting{
+				"namespace": {"mdai-env", sourceEnv},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.config != "" {
+				writeTestConfig(t, tt.config)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cmd, err := NewRootCommand()
+			require.NoError(t, err)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			profile, settings, err := loadSettings(cmd)
+			require.NoError(t, err)
+			require.Equal(t, tt.profile, profile)
+			for key, want := range tt.want {
+				require.Equal(t, want, settings[key], key)
+			}
+		})
+	}
+}
+
+func TestLoadLegacyNamespace(t *testing.T) {
+	writeTestConfig(t, "current-profile: staging\nnamespace: mdai-legacy\nprofiles:\n  staging:\n    kubecontext: kind-staging\n")
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.Empty(t, cfg.Namespace)
+	require.Equal(t, &config.Profile{Namespace: "mdai-legacy", Kubecontext: "kind-staging"}, cfg.Profile("staging"))
+}
@@ -1,18 +1,237 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/charmbracelet/huh"
+	"github.com/decisiveai/mdai-cli/internal/config"
+	"github.com/decisiveai/mdai-cli/internal/configure"
+	"github.com/decisiveai/mdai-cli/internal/operator"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 func NewConfigureCommand() *cobra.Command {
+	flags := configureFlags{}
 	cmd := &cobra.Command{
-		Use:   "configure",
-		Short: "",
-		Long:  "",
-		Run: func(_ *cobra.Command, _ []string) {
+		GroupID: "configuration",
+		Use:     "configure [--answers FILE] [--manifest FILE] [--apply]",
+		Short:   "set up mdai with a wizard",
+		Long: `pick the kube context, cluster profile (kind or eks), ingress settings, exporters and datalyzer,
+then write the engine manifest and the mdai cli profile; with --answers the wizard runs unattended`,
+		Example: `  mdai configure                                   # run the wizard
+  mdai configure --save-answers answers.yaml       # run the wizard and keep the answers for later
+  mdai configure --answers answers.yaml --apply    # configure from an answers file and apply the engine
+  mdai configure --profile eks --manifest eks.yaml # write the eks profile and manifest`,
+		Args: cobra.NoArgs,
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+			createsProfile:     "",
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			answers := newAnswers()
+			if flags.answers != "" {
+				var err error
+				if answers, err = configure.LoadAnswers(flags.answers); err != nil {
+					return err
+				}
+			}
+			fillAnswers(ctx, answers)
+			if flags.answers == "" {
+				if err := configureWizard(ctx, answers); err != nil {
+					return fmt.Errorf("configure failed: %w", err)
+				}
+			}
+			if err := answers.Validate(); err != nil {
+				return fmt.Errorf("invalid answers: %w", err)
+			}
+			if flags.saveAnswers != "" {
+				if err := answers.Save(flags.saveAnswers); err != nil {
+					return err
+				}
+			}
+			return mdaiConfigure(ctx, answers, flags)
 		},
 	}
-	cmd.Hidden = true
+	cmd.Flags().StringVar(&flags.answers, "answers", "", "answers file to configure from without prompting")
+	cmd.Flags().StringVar(&flags.saveAnswers, "save-answers", "", "write the answers to a file for later --answers runs")
+	cmd.Flags().StringVar(&flags.manifest, "manifest", filepath.Join(filepath.Dir(config.Path()), "engine.yaml"), "where to write the engine manifest")
+	cmd.Flags().BoolVar(&flags.apply, "apply", false, "apply the engine manifest to the cluster")
+
+	cmd.MarkFlagsMutuallyExclusive("answers", "save-answers")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
 
 	return cmd
 }
+
+func newAnswers() *configure.Answers {
+	return &configure.Answers{
+		ClusterProfile: configure.ClusterKind,
+		IngressClass:   "alb",
+		Exporters:      []string{"debug"},
+	}
+}
+
+// fillAnswers defaults the kube context and namespace to those mdai runs with.
+func fillAnswers(ctx context.Context, answers *configure.Answers) {
+	if answers.KubeContext == "" {
+		answers.KubeContext = ctx.Value(mdaitypes.Kubecontext{}).(string)
+	}
+	if answers.Namespace == "" {
+		answers.Namespace = ctx.Value(mdaitypes.Namespace{}).(string)
+	}
+	if answers.Datalyzer == nil {
+		datalyzer := true
+		answers.Datalyzer = &datalyzer
+	}
+}
+
+func configureWizard(ctx context.Context, answers *configure.Answers) error {
+	contexts := kubecontexts(ctx.Value(mdaitypes.Kubeconfig{}).(string))
+	var kubecontextField huh.Field
+	if len(contexts) > 0 {
+		kubecontextField = huh.NewSelect[string]().
+			Title("kube context").
+			Options(huh.NewOptions(contexts...)...).
+			Value(&answers.KubeContext)
+	} else {
+		kubecontextField = huh.NewInput().
+			Title("kube context").
+			Value(&answers.KubeContext)
+	}
+
+	if answers.CollectorEndpoints == nil {
+		answers.CollectorEndpoints = make(map[string]string)
+	}
+	endpoints := make(map[string]*string)
+	endpointFields := make([]huh.Field, 0, len(configure.GRPCReceivers()))
+	for _, receiver := range configure.GRPCReceivers() {
+		hostname := answers.CollectorEndpoints[receiver]
+		endpoints[receiver] = &hostname
+		endpointFields = append(endpointFields, huh.NewInput().
+			Title(receiver+" receiver hostname").
+			Description("a CNAME of the load balancer, used to reach the "+receiver+" receiver").
+			Validate(configure.ValidateHostname).
+			Value(&hostname))
+	}
+	notEKS := func() bool { return answers.ClusterProfile != configure.ClusterEKS }
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			kubecontextField,
+			huh.NewSelect[string]().
+				Title("cluster profile").
+				Options(huh.NewOptions(configure.ClusterProfiles()...)...).
+				Value(&answers.ClusterProfile),
+			huh.NewInput().
+				Title("namespace").
+				Value(&answers.Namespace),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("certificate ARN").
+				Description("ACM certificate(s) for the grpc endpoints, comma separated").
+				Validate(configure.ValidateCertificateARN).
+				Value(&answers.CertificateARN),
+			huh.NewInput().
+				Title("ingress class").
+				Value(&answers.IngressClass),
+		).WithHideFunc(notEKS),
+		huh.NewGroup(endpointFields...).WithHideFunc(notEKS),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("exporters").
+				Options(huh.NewOptions(configure.Exporters()...)...).
+				Validate(configure.ValidateExporters).
+				Value(&answers.Exporters),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("otlp exporter endpoint").
+				Placeholder("collector.example.com:4317").
+				Value(&answers.OTLPEndpoint),
+		).WithHideFunc(func() bool { return !slices.Contains(answers.Exporters, "otlp") }),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("enable datalyzer?").
+				Affirmative("yes!").
+				Negative("no.").
+				Value(answers.Datalyzer),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return err
+	}
+	for receiver, hostname := range endpoints {
+		answers.CollectorEndpoints[receiver] = *hostname
+	}
+	return nil
+}
+
+func mdaiConfigure(ctx context.Context, answers *configure.Answers, flags configureFlags) error {
+	manifest, _ := embedFS.ReadFile("templates/mdai-operator.yaml")
+	engine, err := answers.Engine(manifest)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(flags.manifest), 0o700); err != nil { //nolint: mnd
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+	if err := os.WriteFile(flags.manifest, engine.Manifest, 0o600); err != nil { //nolint: mnd
+		return fmt.Errorf("failed to write engine manifest: %w", err)
+	}
+	fmt.Printf("engine manifest written to %s\n", PurpleStyle.Render(flags.manifest))
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	name := ctx.Value(mdaitypes.Profile{}).(string)
+	profile := cfg.Profile(name)
+	profile.Kubecontext = answers.KubeContext
+	profile.Namespace = engine.Namespace
+	profile.Engine = engine.Name
+	profile.Collector = engine.Collector
+	if cfg.CurrentProfile == "" {
+		cfg.CurrentProfile = name
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("profile %s written to %s\n", PurpleStyle.Render(name), PurpleStyle.Render(config.Path()))
+
+	if !flags.apply {
+		fmt.Printf("apply it with %s or rerun with --apply\n", PurpleStyle.Render("kubectl apply -f "+flags.manifest))
+		return nil
+	}
+	ctx = context.WithValue(ctx, mdaitypes.Kubecontext{}, answers.KubeContext)
+	ctx = context.WithValue(ctx, mdaitypes.Namespace{}, engine.Namespace)
+	if err := operator.Install(ctx, engine.Manifest); err != nil {
+		return fmt.Errorf("failed to apply engine manifest: %w", err)
+	}
+	fmt.Printf("engine %s applied\n", PurpleStyle.Render(engine.Name))
+	return nil
+}
+
+func kubecontexts(kubeconfig string) []string {
+	apiConfig, err := clientcmd.LoadFromFile(kubeconfig)
+	if err != nil {
+		return nil
+	}
+	contexts := make([]string, 0, len(apiConfig.Contexts))
+	for name := range apiConfig.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+	return contexts
+}
@@ -0,0 +1,8 @@
+package cmd
+
+type configureFlags struct {
+	answers     string
+	saveAnswers string
+	manifest    string
+	apply       bool
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConfigureCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "configure command with args",
+			args: []string{"configure", "eks"},
+			err:  errors.New(`unknown command "eks" for "mdai configure"`),
+		},
+		{
+			name: "configure command with both answers and save-answers flags",
+			args: []string{"configure", "--answers", "in.yaml", "--save-answers", "out.yaml"},
+			err:  errors.New("if any flags in the group [answers save-answers] are set none of the others can be; [answers save-answers] were all set"),
+		},
+	}
+
+	errTests.Run(t)
+}
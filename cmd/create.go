@@ -1,18 +1,197 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/decisiveai/mdai-cli/internal/configure"
+	"github.com/decisiveai/mdai-cli/internal/operator"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
+	mydecisivev1 "github.com/decisiveai/mydecisive-engine-operator/api/v1"
+	opentelemetry "github.com/decisiveai/opentelemetry-operator/apis/v1alpha1"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func NewCreateCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "create",
-		Short: "",
-		Long:  "",
-		Run: func(_ *cobra.Command, _ []string) {
+		GroupID: "configuration",
+		Use:     "create",
+		Short:   "create engines and collectors",
+		Long:    "create a MyDecisiveEngine from the mdai template, or add a collector to an engine",
+		Example: `  mdai create engine staging                               # create an engine with a debug exporter
+  mdai create engine staging --wizard                      # pick the engine settings interactively
+  mdai create engine prod --answers prod.yaml --dry-run    # print the engine an answers file produces
+  mdai create collector edge --engine staging --replicas 3 # add a collector with the starter config
+  mdai create collector edge --config edge.yaml            # add a collector with its own config`,
+	}
+
+	cmd.AddCommand(
+		newCreateEngineCommand(),
+		newCreateCollectorCommand(),
+	)
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newCreateEngineCommand() *cobra.Command {
+	flags := createEngineFlags{}
+	cmd := &cobra.Command{
+		Use:   "engine NAME [--answers FILE | --wizard] [--dry-run]",
+		Short: "create an engine",
+		Long:  "create a MyDecisiveEngine from the mdai template, configured with flags, an answers file (see mdai configure) or a wizard",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			answers := &configure.Answers{
+				ClusterProfile:     flags.clusterProfile,
+				CertificateARN:     flags.certificateARN,
+				CollectorEndpoints: flags.endpoints,
+				IngressClass:       flags.ingressClass,
+				Exporters:          flags.exporters,
+				OTLPEndpoint:       flags.otlpEndpoint,
+				Datalyzer:          &flags.datalyzer,
+			}
+			if flags.answers != "" {
+				var err error
+				if answers, err = configure.LoadAnswers(flags.answers); err != nil {
+					return err
+				}
+			}
+			answers.EngineName = args[0]
+			fillAnswers(ctx, answers)
+			if flags.wizard {
+				if err := configureWizard(ctx, answers); err != nil {
+					return fmt.Errorf("create failed: %w", err)
+				}
+			}
+			if err := answers.Validate(); err != nil {
+				return fmt.Errorf("invalid engine settings: %w", err)
+			}
+
+			manifest, _ := embedFS.ReadFile("templates/mdai-operator.yaml")
+			engine, err := answers.Engine(manifest)
+			if err != nil {
+				return err
+			}
+			if flags.dryRun {
+				fmt.Print(string(engine.Manifest))
+				return nil
+			}
+			ctx = context.WithValue(ctx, mdaitypes.Kubecontext{}, answers.KubeContext)
+			ctx = context.WithValue(ctx, mdaitypes.Namespace{}, engine.Namespace)
+			return mdaiCreateEngine(ctx, engine)
+		},
+	}
+	cmd.Flags().StringVar(&flags.answers, "answers", "", "answers file with the engine settings")
+	cmd.Flags().BoolVar(&flags.wizard, "wizard", false, "pick the engine settings interactively")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "print the engine manifest instead of creating it")
+	cmd.Flags().StringVar(&flags.clusterProfile, "cluster-profile", configure.ClusterKind, "cluster profile ["+strings.Join(configure.ClusterProfiles(), ", ")+"]")
+	cmd.Flags().StringVar(&flags.certificateARN, "certificate-arn", "", "ACM certificate ARN for the grpc endpoints (eks)")
+	cmd.Flags().StringToStringVar(&flags.endpoints, "endpoint", nil, "ingress hostname of a grpc receiver, RECEIVER=HOSTNAME (eks)")
+	cmd.Flags().StringVar(&flags.ingressClass, "ingress-class", "alb", "ingress class (eks)")
+	cmd.Flags().StringSliceVar(&flags.exporters, "exporters", []string{"debug"}, "exporters ["+strings.Join(configure.Exporters(), ", ")+"]")
+	cmd.Flags().StringVar(&flags.otlpEndpoint, "otlp-endpoint", "", "endpoint of the otlp exporter")
+	cmd.Flags().BoolVar(&flags.datalyzer, "datalyzer", true, "enable datalyzer")
+
+	cmd.MarkFlagsMutuallyExclusive("answers", "wizard")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func mdaiCreateEngine(ctx context.Context, engine *configure.Engine) error {
+	engines, err := operator.ListEngines(ctx)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(engines, func(e mydecisivev1.MyDecisiveEngine) bool { return e.Name == engine.Name }) {
+		return fmt.Errorf("engine %s already exists in namespace %s", engine.Name, engine.Namespace)
+	}
+	if err := operator.Install(ctx, engine.Manifest); err != nil {
+		return fmt.Errorf("failed to create engine: %w", err)
+	}
+	fmt.Printf("engine %s created in namespace %s\n", PurpleStyle.Render(engine.Name), PurpleStyle.Render(engine.Namespace))
+	return nil
+}
+
+func newCreateCollectorCommand() *cobra.Command {
+	flags := createCollectorFlags{}
+	cmd := &cobra.Command{
+		Use:   "collector NAME [--replicas N] [--port NAME=PORT]... [--config FILE]",
+		Short: "add a collector to an engine",
+		Long:  "add a collector to the engine selected with --engine, with a starter otlp to debug config unless --config is given",
+		Args:  cobra.ExactArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if flags.replicas < 1 {
+				return fmt.Errorf("invalid replicas: %d", flags.replicas)
+			}
+			for name, port := range flags.ports {
+				if port < 1 || port > 65535 {
+					return fmt.Errorf("invalid port: %s=%d", name, port)
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			otelConfig, _ := embedFS.ReadFile("templates/collector-config.yaml")
+			if flags.config != "" {
+				var err error
+				if otelConfig, err = os.ReadFile(flags.config); err != nil {
+					return fmt.Errorf("failed to read collector config: %w", err)
+				}
+			}
+			var parsed map[string]any
+			if err := yaml.Unmarshal(otelConfig, &parsed); err != nil {
+				return fmt.Errorf("invalid collector config: %w", err)
+			}
+
+			names := make([]string, 0, len(flags.ports))
+			for name := range flags.ports {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			ports := make([]corev1.ServicePort, 0, len(names))
+			for _, name := range names {
+				ports = append(ports, corev1.ServicePort{Name: name, Port: int32(flags.ports[name]), Protocol: corev1.ProtocolTCP}) //nolint: gosec
+			}
+
+			collector := mydecisivev1.Collector{
+				Name:           args[0],
+				Enabled:        true,
+				MeasureVolumes: flags.datalyzer,
+				Spec: opentelemetry.OpenTelemetryCollectorSpec{
+					Replicas: &flags.replicas,
+					Ports:    ports,
+					Config:   string(otelConfig),
+				},
+			}
+			if err := operator.CreateCollector(ctx, collector); err != nil {
+				return err
+			}
+			fmt.Printf("collector %s created\n", PurpleStyle.Render(args[0]))
+			return nil
 		},
 	}
-	cmd.Hidden = true
+	cmd.Flags().Int32Var(&flags.replicas, "replicas", 1, "number of collector replicas")
+	cmd.Flags().StringToIntVar(&flags.ports, "port", map[string]int{"otlp-grpc": 4317, "otlp-http": 4318}, "service port, NAME=PORT") //nolint: mnd
+	cmd.Flags().StringVar(&flags.config, "config", "", "otel collector config file")
+	cmd.Flags().BoolVar(&flags.datalyzer, "datalyzer", false, "enable datalyzer for the collector")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
 
 	return cmd
 }
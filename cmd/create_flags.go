@@ -0,0 +1,21 @@
+package cmd
+
+type createEngineFlags struct {
+	answers        string
+	wizard         bool
+	dryRun         bool
+	clusterProfile string
+	certificateARN string
+	endpoints      map[string]string
+	ingressClass   string
+	exporters      []string
+	otlpEndpoint   string
+	datalyzer      bool
+}
+
+type createCollectorFlags struct {
+	replicas  int32
+	ports     map[string]int
+	config    string
+	datalyzer bool
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCreateCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "create engine command without name",
+			args: []string{"create", "engine"},
+			err:  errors.New("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "create engine command with both answers and wizard flags",
+			args: []string{"create", "engine", "staging", "--answers", "answers.yaml", "--wizard"},
+			err:  errors.New("if any flags in the group [answers wizard] are set none of the others can be; [answers wizard] were all set"),
+		},
+		{
+			name: "create collector command without name",
+			args: []string{"create", "collector"},
+			err:  errors.New("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "create collector command with invalid replicas",
+			args: []string{"create", "collector", "edge", "--replicas", "0"},
+			err:  errors.New("invalid replicas: 0"),
+		},
+		{
+			name: "create collector command with invalid port",
+			args: []string{"create", "collector", "edge", "--port", "otlp-grpc=70000"},
+			err:  errors.New("invalid port: otlp-grpc=70000"),
+		},
+	}
+
+	errTests.Run(t)
+}
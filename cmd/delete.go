@@ -1,18 +1,181 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/decisiveai/mdai-cli/internal/config"
+	"github.com/decisiveai/mdai-cli/internal/operator"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
+	mydecisivev1 "github.com/decisiveai/mydecisive-engine-operator/api/v1"
 	"github.com/spf13/cobra"
 )
 
 func NewDeleteCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete",
-		Short: "",
-		Long:  ``,
-		Run: func(_ *cobra.Command, _ []string) {
+		GroupID: "configuration",
+		Use:     "delete",
+		Aliases: []string{"remove"},
+		Short:   "delete engines and collectors",
+		Long:    "delete a MyDecisiveEngine, or remove a collector from an engine",
+		Example: `  mdai delete engine staging                     # delete the staging engine
+  mdai delete collector edge --engine staging    # remove the edge collector from the staging engine
+  mdai delete collector edge --force --confirm   # remove it even though it still has telemetry filters`,
+	}
+
+	cmd.AddCommand(
+		newDeleteEngineCommand(),
+		newDeleteCollectorCommand(),
+	)
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newDeleteEngineCommand() *cobra.Command {
+	flags := deleteFlags{}
+	cmd := &cobra.Command{
+		Use:   "engine NAME [--confirm] [--force]",
+		Short: "delete an engine",
+		Long:  "delete a MyDecisiveEngine and, through it, its collectors; engines with telemetry filters need --force",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.WithValue(cmd.Context(), mdaitypes.Engine{}, args[0])
+			engine, err := operator.GetOperator(ctx)
+			if err != nil {
+				return err
+			}
+
+			var collectors []string
+			filters := 0
+			for _, collector := range engine.Spec.TelemetryModule.Collectors {
+				collectors = append(collectors, collector.Name)
+				filters += telemetryFilterCount(&collector)
+			}
+			if filters > 0 && !flags.force {
+				return fmt.Errorf("engine %s still has %d telemetry filter(s), use --force to delete it anyway", engine.Name, filters)
+			}
+
+			if err := confirmDelete(ctx, &flags.confirm,
+				fmt.Sprintf("Delete engine %s?", engine.Name),
+				fmt.Sprintf("collectors: %s\n", strings.Join(collectors, ", "))); err != nil {
+				return err
+			}
+			if err := operator.DeleteEngine(ctx, engine); err != nil {
+				return err
+			}
+			fmt.Printf("engine %s deleted\n", PurpleStyle.Render(engine.Name))
+			warnProfilesUsing(func(profile *config.Profile) bool { return profile.Engine == engine.Name }, "engine "+engine.Name)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&flags.confirm, "confirm", false, "confirm deletion")
+	cmd.Flags().BoolVar(&flags.force, "force", false, "delete even if telemetry filters are configured")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newDeleteCollectorCommand() *cobra.Command {
+	flags := deleteFlags{}
+	cmd := &cobra.Command{
+		Use:   "collector NAME [--confirm] [--force]",
+		Short: "remove a collector from an engine",
+		Long:  "remove a collector from the engine selected with --engine; collectors with telemetry filters need --force and the last collector of an engine cannot be removed",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			engine, err := operator.GetOperator(ctx)
+			if err != nil {
+				return err
+			}
+
+			var collector *mydecisivev1.Collector
+			for i := range engine.Spec.TelemetryModule.Collectors {
+				if engine.Spec.TelemetryModule.Collectors[i].Name == args[0] {
+					collector = &engine.Spec.TelemetryModule.Collectors[i]
+				}
+			}
+			switch {
+			case collector == nil:
+				return fmt.Errorf(`collector "%s" not found in engine %s`, args[0], engine.Name)
+			case len(engine.Spec.TelemetryModule.Collectors) == 1:
+				return fmt.Errorf("collector %s is the only collector of engine %s, delete the engine instead", args[0], engine.Name)
+			case telemetryFilterCount(collector) > 0 && !flags.force:
+				return fmt.Errorf("collector %s still has %d telemetry filter(s), use --force to delete it anyway", args[0], telemetryFilterCount(collector))
+			}
+
+			if err := confirmDelete(ctx, &flags.confirm,
+				fmt.Sprintf("Remove collector %s from engine %s?", collector.Name, engine.Name),
+				fmt.Sprintf("enabled: %t\ndatalyzer: %t\n", collector.Enabled, collector.MeasureVolumes)); err != nil {
+				return err
+			}
+			ctx = context.WithValue(ctx, mdaitypes.Engine{}, engine.Name)
+			if err := operator.DeleteCollector(ctx, collector.Name); err != nil {
+				return err
+			}
+			fmt.Printf("collector %s removed from engine %s\n", PurpleStyle.Render(collector.Name), PurpleStyle.Render(engine.Name))
+			warnProfilesUsing(func(profile *config.Profile) bool {
+				return profile.Collector == collector.Name && (profile.Engine == "" || profile.Engine == engine.Name)
+			}, "collector "+collector.Name)
+			return nil
 		},
 	}
-	cmd.Hidden = true
+	cmd.Flags().BoolVar(&flags.confirm, "confirm", false, "confirm deletion")
+	cmd.Flags().BoolVar(&flags.force, "force", false, "delete even if telemetry filters are configured")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
 
 	return cmd
 }
+
+func confirmDelete(ctx context.Context, confirm *bool, title, description string) error {
+	if !*confirm {
+		kubeconfig := ctx.Value(mdaitypes.Kubeconfig{}).(string)
+		kubecontext := ctx.Value(mdaitypes.Kubecontext{}).(string)
+		if err := huh.NewConfirm().
+			Title(title).
+			Description(fmt.Sprintf("%skubeconfig: %s\nkubecontext: %s\n", description, kubeconfig, kubecontext)).
+			Negative("No!").
+			Affirmative("Yes.").
+			Value(confirm).Run(); err != nil {
+			return fmt.Errorf("delete failed: %w", err)
+		}
+	}
+	if !*confirm {
+		return errors.New("aborting deletion")
+	}
+	return nil
+}
+
+func telemetryFilterCount(collector *mydecisivev1.Collector) int {
+	if collector.TelemetryFiltering == nil || collector.TelemetryFiltering.Filters == nil {
+		return 0
+	}
+	return len(*collector.TelemetryFiltering.Filters)
+}
+
+// warnProfilesUsing points out profiles that still select something that was deleted.
+func warnProfilesUsing(uses func(*config.Profile) bool, what string) {
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, name := range cfg.ProfileNames() {
+		if uses(cfg.Profile(name)) {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		fmt.Printf("profiles %s still select %s, change them with mdai config set\n", PurpleStyle.Render(strings.Join(names, ", ")), what)
+	}
+}
@@ -0,0 +1,6 @@
+package cmd
+
+type deleteFlags struct {
+	confirm bool
+	force   bool
+}
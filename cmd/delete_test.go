@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeleteCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "delete engine command without name",
+			args: []string{"delete", "engine"},
+			err:  errors.New("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "delete collector command with too many args",
+			args: []string{"delete", "collector", "edge", "gateway"},
+			err:  errors.New("accepts 1 arg(s), received 2"),
+		},
+		{
+			name: "remove alias without subcommand args",
+			args: []string{"remove", "engine"},
+			err:  errors.New("accepts 1 arg(s), received 0"),
+		},
+	}
+
+	errTests.Run(t)
+}
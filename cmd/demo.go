@@ -0,0 +1,462 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/log"
+	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
+	"github.com/decisiveai/mdai-cli/internal/progress"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	demoChart         = "opentelemetry-demo"
+	demoTimeout       = 5 * time.Minute
+	gatewayCollector  = "gateway-collector"
+	telemetrygenImage = "ghcr.io/open-telemetry/opentelemetry-collector-contrib/telemetrygen:v0.101.0"
+	demoLoadSelector  = "app.kubernetes.io/name=telemetrygen,app.kubernetes.io/part-of=mdai-demo"
+)
+
+func NewDemoCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		GroupID: "installation",
+		Use:     "demo",
+		Short:   "run the OpenTelemetry demo app against MDAI",
+		Long:    "install the OpenTelemetry demo app wired to the MDAI gateway collector, or generate synthetic telemetry without it",
+		Example: `  mdai demo install                 # install the OpenTelemetry demo app
+  mdai demo status                  # show the demo app and load generator status
+  mdai demo load --rate 50          # send synthetic traces, metrics and logs
+  mdai demo load --stop             # stop the load generators
+  mdai demo uninstall --confirm     # uninstall the OpenTelemetry demo app`,
+	}
+
+	cmd.AddCommand(
+		newDemoInstallCommand(),
+		newDemoUninstallCommand(),
+		newDemoStatusCommand(),
+		newDemoLoadCommand(),
+	)
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newDemoInstallCommand() *cobra.Command {
+	flags := demoInstallFlags{}
+	cmd := &cobra.Command{
+		Use:   "install [--confirm] [--timeout DURATION] [--chart-repo REPO]",
+		Short: "install the OpenTelemetry demo app",
+		Long:  "install the OpenTelemetry demo app, sending its telemetry to the MDAI gateway collector",
+		Args:  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateWaitFlags(flags.timeout, 1)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := demoContext(cmd.Context(), flags.debug, flags.timeout)
+			if flags.chartRepo != "" {
+				ctx = context.WithValue(ctx, mdaitypes.ChartRepo{}, flags.chartRepo)
+			}
+			ctx = context.WithValue(ctx, mdaitypes.InsecureSkipVerify{}, flags.insecureSkipVerify)
+			if !flags.confirm {
+				if err := confirmDemo(ctx, "Install the OpenTelemetry demo app?", &flags.confirm); err != nil {
+					return fmt.Errorf("demo install failed: %w", err)
+				}
+			}
+			if !flags.confirm {
+				return errors.New("aborting demo installation")
+			}
+			return mdaiDemoInstall(ctx)
+		},
+	}
+	cmd.Flags().BoolVar(&flags.confirm, "confirm", false, "confirm installation")
+	cmd.Flags().BoolVar(&flags.debug, "debug", false, "debug mode")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", demoTimeout, "time to wait for the demo app to become ready")
+	cmd.Flags().StringVar(&flags.chartRepo, "chart-repo", "", chartRepoUsage)
+	cmd.Flags().BoolVar(&flags.insecureSkipVerify, "insecure-skip-verify", false, insecureSkipVerifyUsage)
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newDemoUninstallCommand() *cobra.Command {
+	flags := demoUninstallFlags{}
+	cmd := &cobra.Command{
+		Use:   "uninstall [--confirm] [--timeout DURATION]",
+		Short: "uninstall the OpenTelemetry demo app",
+		Long:  "uninstall the OpenTelemetry demo app and stop any load generators",
+		Args:  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateWaitFlags(flags.timeout, 1)
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := demoContext(cmd.Context(), flags.debug, flags.timeout)
+			if !flags.confirm {
+				if err := confirmDemo(ctx, "Uninstall the OpenTelemetry demo app?", &flags.confirm); err != nil {
+					return fmt.Errorf("demo uninstall failed: %w", err)
+				}
+			}
+			if !flags.confirm {
+				return errors.New("aborting demo uninstallation")
+			}
+			return mdaiDemoUninstall(ctx)
+		},
+	}
+	cmd.Flags().BoolVar(&flags.confirm, "confirm", false, "confirm uninstallation")
+	cmd.Flags().BoolVar(&flags.debug, "debug", false, "debug mode")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", kubehelper.DefaultTimeout, "time to wait for the demo app to be removed")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newDemoStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "show the OpenTelemetry demo app status",
+		Long:  "show the OpenTelemetry demo app release, its components and running load generators",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return mdaiDemoStatus(cmd.Context())
+		},
+	}
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newDemoLoadCommand() *cobra.Command {
+	flags := demoLoadFlags{}
+	cmd := &cobra.Command{
+		Use:   "load [--signals SIGNALS] [--rate RATE] [--workers WORKERS] [--duration DURATION] [--stop]",
+		Short: "generate synthetic telemetry",
+		Long:  "generate synthetic telemetry with telemetrygen jobs sending to the MDAI gateway collector, without installing the demo app",
+		Example: `  mdai demo load                               # send traces, metrics and logs for 5 minutes
+  mdai demo load --signals traces --rate 100   # send 100 spans per second per worker
+  mdai demo load --duration 1h --workers 4     # send with 4 workers for an hour
+  mdai demo load --stop                        # stop all load generators`,
+		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if flags.stop {
+				return nil
+			}
+			for _, signal := range flags.signals {
+				if !slices.Contains(supportedPhases(), signal) {
+					return fmt.Errorf("invalid signal: %s", signal)
+				}
+			}
+			switch {
+			case flags.rate < 1:
+				return fmt.Errorf("invalid rate: %d", flags.rate)
+			case flags.workers < 1:
+				return fmt.Errorf("invalid workers: %d", flags.workers)
+			case flags.duration <= 0:
+				return fmt.Errorf("invalid duration: %s", flags.duration)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			helper, err := kubehelper.New(kubehelper.WithContext(ctx))
+			if err != nil {
+				return fmt.Errorf("failed to initialize kubehelper: %w", err)
+			}
+			if flags.stop {
+				if err := helper.DeleteJobs(ctx, helper.Namespace(), demoLoadSelector); err != nil {
+					return fmt.Errorf("failed to stop load generators: %w", err)
+				}
+				fmt.Println("load generators stopped")
+				return nil
+			}
+			return mdaiDemoLoad(ctx, helper, flags)
+		},
+	}
+	cmd.Flags().StringSliceVar(&flags.signals, "signals", supportedPhases(), "signals to generate, comma separated")
+	cmd.Flags().IntVar(&flags.rate, "rate", 10, "items per second per worker")
+	cmd.Flags().IntVar(&flags.workers, "workers", 1, "number of workers per signal")
+	cmd.Flags().DurationVar(&flags.duration, "duration", 5*time.Minute, "how long to generate telemetry for")
+	cmd.Flags().BoolVar(&flags.stop, "stop", false, "stop running load generators")
+
+	cmd.MarkFlagsMutuallyExclusive("stop", "signals")
+	cmd.MarkFlagsMutuallyExclusive("stop", "rate")
+	cmd.MarkFlagsMutuallyExclusive("stop", "workers")
+	cmd.MarkFlagsMutuallyExclusive("stop", "duration")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func demoContext(ctx context.Context, debug bool, timeout time.Duration) context.Context {
+	logger := log.New(os.Stderr)
+	if debug {
+		logger.SetLevel(log.DebugLevel)
+	}
+	ctx = log.WithContext(ctx, logger)
+	return context.WithValue(ctx, mdaitypes.Timeout{}, timeout)
+}
+
+func confirmDemo(ctx context.Context, title string, confirm *bool) error {
+	kubeconfig := ctx.Value(mdaitypes.Kubeconfig{}).(string)
+	kubecontext := ctx.Value(mdaitypes.Kubecontext{}).(string)
+	return huh.NewConfirm().
+		Title(title).
+		Description(fmt.Sprintf("kubeconfig: %s\nkubecontext: %s\n", kubeconfig, kubecontext)).
+		Affirmative("Yes!").
+		Negative("No.").
+		Value(confirm).Run()
+}
+
+func mdaiDemoInstall(ctx context.Context) error {
+	helper, err := kubehelper.New(kubehelper.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	if err := checkGatewayCollector(ctx, helper); err != nil {
+		return err
+	}
+
+	helmclient := mdaihelm.NewClient(mdaihelm.WithContext(ctx))
+	namespace, err := helmclient.ChartNamespace(demoChart)
+	if err != nil {
+		return err
+	}
+
+	installCtx, cancel := context.WithCancelCause(ctx)
+	go func() {
+		cancel(helmclient.InstallChart(demoChart))
+	}()
+
+	title := "installing OpenTelemetry demo app 🔭"
+	watcher := progress.NewWatcher(helper, namespace, nil)
+	if err := progress.Run(installCtx, watcher, title, ProgressStyles); err != nil {
+		return fmt.Errorf("failed to install demo app: %w", err)
+	}
+
+	if installCtx.Err() != nil && !errors.Is(context.Cause(installCtx), context.Canceled) {
+		fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(red).Render(DisabledString) + " " + title)
+		return fmt.Errorf("failed to install demo app: %w", unverifiedHint(context.Cause(installCtx)))
+	}
+
+	fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(green).Render(EnabledString) + " " + title)
+	fmt.Printf(" telemetry from namespace %s is sent to %s\n",
+		PurpleStyle.Render(namespace),
+		PurpleStyle.Render(gatewayEndpoint(helper.Namespace())),
+	)
+	return nil
+}
+
+func mdaiDemoUninstall(ctx context.Context) error {
+	helper, err := kubehelper.New(kubehelper.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	if err := helper.DeleteJobs(ctx, helper.Namespace(), demoLoadSelector); err != nil {
+		return fmt.Errorf("failed to stop load generators: %w", err)
+	}
+
+	spinnerCtx, cancel := context.WithCancelCause(ctx)
+	title := "uninstalling OpenTelemetry demo app 🔭"
+
+	go func() {
+		helmclient := mdaihelm.NewClient(mdaihelm.WithContext(ctx))
+		cancel(helmclient.UninstallChart(demoChart))
+	}()
+	if err := spinner.New().
+		Title(title).
+		TitleStyle(lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00020A", Dark: "#D3D3D3"})).
+		Style(lipgloss.NewStyle().PaddingLeft(1).Foreground(purple)).
+		Context(spinnerCtx).
+		Run(); err != nil {
+		return fmt.Errorf("failed to uninstall demo app: %w", err)
+	}
+
+	if spinnerCtx.Err() != nil && !errors.Is(context.Cause(spinnerCtx), context.Canceled) {
+		fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(red).Render(DisabledString) + " " + title)
+		return fmt.Errorf("failed to uninstall demo app: %w", context.Cause(spinnerCtx))
+	}
+
+	fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(green).Render(EnabledString) + " " + title)
+	return nil
+}
+
+func mdaiDemoStatus(ctx context.Context) error {
+	helper, err := kubehelper.New(kubehelper.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	helmclient := mdaihelm.NewClient(mdaihelm.WithContext(ctx))
+
+	rel, err := helmclient.Release(demoChart)
+	switch {
+	case errors.Is(err, driver.ErrReleaseNotFound):
+		fmt.Println("OpenTelemetry demo app is not installed")
+	case err != nil:
+		return fmt.Errorf("failed to get demo release: %w", err)
+	default:
+		fmt.Printf("release: %s %s (%s) in namespace %s\n",
+			PurpleStyle.Render(rel.Chart.Metadata.Name),
+			PurpleStyle.Render(rel.Chart.Metadata.Version),
+			rel.Info.Status,
+			PurpleStyle.Render(rel.Namespace),
+		)
+		components, err := progress.NewWatcher(helper, rel.Namespace, nil).Snapshot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get demo components: %w", err)
+		}
+		t := styledTable().Headers(demoComponentHeaders()...)
+		for _, component := range components {
+			ready := DisabledString
+			if component.Ready {
+				ready = EnabledString
+			}
+			t.Row(component.Name, ready, component.Status)
+		}
+		fmt.Println(t)
+	}
+
+	jobs, err := helper.ListJobs(ctx, helper.Namespace(), demoLoadSelector)
+	if err != nil {
+		return fmt.Errorf("failed to list load generators: %w", err)
+	}
+	if len(jobs.Items) == 0 {
+		fmt.Println("no load generators running")
+		return nil
+	}
+	t := styledTable().Headers(demoLoadHeaders()...)
+	for _, job := range jobs.Items {
+		started := NoDataString
+		if job.Status.StartTime != nil {
+			started = job.Status.StartTime.Format(time.RFC3339)
+		}
+		t.Row(
+			job.Name,
+			job.Labels["app.kubernetes.io/component"],
+			strconv.Itoa(int(job.Status.Active)),
+			strconv.Itoa(int(job.Status.Succeeded)),
+			strconv.Itoa(int(job.Status.Failed)),
+			started,
+		)
+	}
+	fmt.Println(t)
+	return nil
+}
+
+func mdaiDemoLoad(ctx context.Context, helper *kubehelper.Helper, flags demoLoadFlags) error {
+	if err := checkGatewayCollector(ctx, helper); err != nil {
+		return err
+	}
+	for _, signal := range flags.signals {
+		job, err := helper.CreateJob(ctx, telemetrygenJob(helper.Namespace(), signal, flags))
+		if err != nil {
+			return fmt.Errorf("failed to create %s load generator: %w", signal, err)
+		}
+		fmt.Printf("%s generating %s for %s (%s)\n",
+			lipgloss.NewStyle().Foreground(green).Render(EnabledString),
+			PurpleStyle.Render(signal),
+			flags.duration,
+			job.Name,
+		)
+	}
+	fmt.Printf(" sending to %s, stop with %s\n",
+		PurpleStyle.Render(gatewayEndpoint(helper.Namespace())),
+		PurpleStyle.Render("mdai demo load --stop"),
+	)
+	return nil
+}
+
+func checkGatewayCollector(ctx context.Context, helper *kubehelper.Helper) error {
+	if _, err := helper.GetDeployment(ctx, gatewayCollector, helper.Namespace()); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("MDAI gateway collector not found in namespace %s, install MDAI first", helper.Namespace())
+		}
+		return fmt.Errorf("failed to get gateway collector: %w", err)
+	}
+	return nil
+}
+
+func gatewayEndpoint(namespace string) string {
+	return gatewayCollector + "." + namespace + ".svc.cluster.local:4317"
+}
+
+func telemetrygenJob(namespace, signal string, flags demoLoadFlags) *batchv1.Job {
+	labels := map[string]string{
+		"app.kubernetes.io/name":      "telemetrygen",
+		"app.kubernetes.io/part-of":   "mdai-demo",
+		"app.kubernetes.io/component": signal,
+	}
+	var backoffLimit int32
+	var ttl int32 = 600
+	deadline := int64((flags.duration + time.Minute).Seconds())
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "mdai-demo-load-" + signal + "-",
+			Namespace:    namespace,
+			Labels:       labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			ActiveDeadlineSeconds:   &deadline,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:  "telemetrygen",
+						Image: telemetrygenImage,
+						Args: []string{
+							signal,
+							"--otlp-endpoint", gatewayEndpoint(namespace),
+							"--otlp-insecure",
+							"--rate", strconv.Itoa(flags.rate),
+							"--workers", strconv.Itoa(flags.workers),
+							"--duration", flags.duration.String(),
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func styledTable() *table.Table {
+	return table.New().
+		BorderHeader(false).
+		Border(lipgloss.HiddenBorder()).
+		StyleFunc(func(row, _ int) lipgloss.Style {
+			switch {
+			case row == 0:
+				return HeaderStyle
+			case row%2 == 0:
+				return EvenRowStyle
+			default:
+				return OddRowStyle
+			}
+		})
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import "time"
+
+type demoInstallFlags struct {
+	confirm            bool
+	debug              bool
+	timeout            time.Duration
+	chartRepo          string
+	insecureSkipVerify bool
+}
+
+type demoUninstallFlags struct {
+	confirm bool
+	debug   bool
+	timeout time.Duration
+}
+
+type demoLoadFlags struct {
+	signals  []string
+	rate     int
+	workers  int
+	duration time.Duration
+	stop     bool
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDemoCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "demo install with args",
+			args: []string{"demo", "install", "now"},
+			err:  errors.New(`unknown command "now" for "mdai demo install"`),
+		},
+		{
+			name: "demo install with non-positive --timeout",
+			args: []string{"demo", "install", "--timeout", "0s"},
+			err:  errors.New("invalid timeout: 0s"),
+		},
+		{
+			name: "demo uninstall with non-positive --timeout",
+			args: []string{"demo", "uninstall", "--timeout", "-1m"},
+			err:  errors.New("invalid timeout: -1m0s"),
+		},
+		{
+			name: "demo load with invalid signal",
+			args: []string{"demo", "load", "--signals", "traces,profiles"},
+			err:  errors.New("invalid signal: profiles"),
+		},
+		{
+			name: "demo load with non-positive --rate",
+			args: []string{"demo", "load", "--rate", "0"},
+			err:  errors.New("invalid rate: 0"),
+		},
+		{
+			name: "demo load with non-positive --workers",
+			args: []string{"demo", "load", "--workers", "0"},
+			err:  errors.New("invalid workers: 0"),
+		},
+		{
+			name: "demo load with non-positive --duration",
+			args: []string{"demo", "load", "--duration", "0s"},
+			err:  errors.New("invalid duration: 0s"),
+		},
+		{
+			name: "demo load with both --stop and --rate",
+			args: []string{"demo", "load", "--stop", "--rate", "5"},
+			err:  errors.New(`if any flags in the group [stop rate] are set none of the others can be; [rate stop] were all set`),
+		},
+	}
+
+	errTests.Run(t)
+}
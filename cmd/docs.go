@@ -19,6 +19,7 @@ func NewDocsCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to create root command: %w", err)
 			}
+			rootCmd.InitDefaultCompletionCmd()
 
 			if flags.md {
 				if err := doc.GenMarkdownTree(rootCmd, "docs/md"); err != nil {
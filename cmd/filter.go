@@ -52,17 +52,18 @@ func NewFilterListCommand() *cobra.Command {
 		Long:  `list telemetry filters`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx := cmd.Context()
-			mdaiOperator, err := operator.GetOperator(ctx)
+			collector, err := operator.GetCollector(ctx)
 			if err != nil {
 				return err
 			}
 
-			hasTelemetryFilters := func(mdaiOperator *v1.MyDecisiveEngine) bool {
-				return mdaiOperator.Spec.TelemetryModule.Collectors[0].TelemetryFiltering != nil &&
-					len(*mdaiOperator.Spec.TelemetryModule.Collectors[0].TelemetryFiltering.Filters) > 0
+			hasTelemetryFilters := func(collector *v1.Collector) bool {
+				return collector.TelemetryFiltering != nil &&
+					collector.TelemetryFiltering.Filters != nil &&
+					len(*collector.TelemetryFiltering.Filters) > 0
 			}
 
-			if !hasTelemetryFilters(mdaiOperator) {
+			if !hasTelemetryFilters(collector) {
 				fmt.Println("No filters found.")
 				return nil
 			}
@@ -109,7 +110,7 @@ func NewFilterListCommand() *cobra.Command {
 
 			var pipelineFilterRows, filterServicerRows [][]string
 
-			for _, filter := range *mdaiOperator.Spec.TelemetryModule.Collectors[0].TelemetryFiltering.Filters {
+			for _, filter := range *collector.TelemetryFiltering.Filters {
 				if flags.onlyService && filter.FilteredServices == nil {
 					continue
 				}
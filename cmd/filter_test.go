@@ -85,6 +85,10 @@ Flags:
   -t, --telemetry strings    telemetry type
 
 Global Flags:
+      --collector string     Collector of the engine to configure, defaults to the first one
+      --engine string        MyDecisiveEngine to configure, required when the namespace has several
       --kubeconfig string    Path to a kubeconfig
       --kubecontext string   Kubernetes context to use
+      --namespace string     Kubernetes namespace MDAI is installed in
+      --profile string       Profile from ~/.mdai/config.yaml to use
 `
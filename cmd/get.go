@@ -1,42 +1,84 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/decisiveai/mdai-cli/internal/operator"
+	"github.com/decisiveai/mdai-cli/internal/otelconfig"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 func NewGetCommand() *cobra.Command {
 	flags := getFlags{}
 	cmd := &cobra.Command{
 		GroupID: "configuration",
-		Use:     "get -c|--config MODULE-NAME",
+		Use:     "get -c|--config MODULE-NAME [-o|--output FORMAT]",
 		Short:   "get a configuration",
 		Long:    "get mdai or otel collector configuration",
-		Example: `  mdai get --config mdai # get mdai configuration
-  mdai get --config otel # get otel configuration`,
+		Example: `  mdai get --config mdai           # get mdai configuration
+  mdai get --config mdai -o yaml   # get the full mdai engine spec
+  mdai get --config otel           # get otel configuration, with credentials masked`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			flags.output = cmd.Context().Value(mdaitypes.Output{}).(string)
+			if !slices.Contains(getOutputs(), flags.output) {
+				if cmd.Flags().Changed("output") {
+					return fmt.Errorf("invalid output: %s", flags.output)
+				}
+				flags.output = ""
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx := cmd.Context()
 
 			switch flags.configType {
 			case "mdai":
+				if flags.output != "" {
+					manifest, err := operator.GetEngineManifest(ctx)
+					if err != nil {
+						return err
+					}
+					engine, err := maskEngineManifest(manifest, flags.output)
+					if err != nil {
+						return err
+					}
+					fmt.Print(engine)
+					return nil
+				}
 				get, err := operator.GetOperator(ctx)
 				if err != nil {
 					return err
 				}
+				collector, err := operator.GetCollector(ctx)
+				if err != nil {
+					return err
+				}
 				fmt.Printf("name           : %s\n", PurpleStyle.Render(get.Name))
 				fmt.Printf("namespace      : %s\n", PurpleStyle.Render(get.Namespace))
-				fmt.Printf("measure volumes: %v\n", PurpleStyle.Render(strconv.FormatBool(get.Spec.TelemetryModule.Collectors[0].MeasureVolumes)))
-				fmt.Printf("enabled        : %v\n", PurpleStyle.Render(strconv.FormatBool(get.Spec.TelemetryModule.Collectors[0].Enabled)))
+				fmt.Printf("collector      : %s\n", PurpleStyle.Render(collector.Name))
+				fmt.Printf("measure volumes: %v\n", PurpleStyle.Render(strconv.FormatBool(collector.MeasureVolumes)))
+				fmt.Printf("enabled        : %v\n", PurpleStyle.Render(strconv.FormatBool(collector.Enabled)))
 			case "otel":
-				get, err := operator.GetOperator(ctx)
+				collector, err := operator.GetCollector(ctx)
+				if err != nil {
+					return err
+				}
+				cfg, err := otelconfig.Parse(collector.Spec.Config)
 				if err != nil {
 					return err
 				}
-				fmt.Println(get.Spec.TelemetryModule.Collectors[0].Spec.Config)
+				cfg.Mask()
+				otelConfig, err := cfg.String()
+				if err != nil {
+					return err
+				}
+				fmt.Print(otelConfig)
 			default:
 				return fmt.Errorf("config type %s is not supported", flags.configType)
 			}
@@ -45,6 +87,7 @@ func NewGetCommand() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&flags.configType, "config", "c", "", "configuration to get ["+strings.Join(supportedGetConfigTypes(), ", ")+"]")
+	cmd.Flags().StringP("output", "o", "", "print the full mdai engine spec ["+strings.Join(getOutputs(), ", ")+"]")
 
 	_ = cmd.MarkFlagRequired("config")
 
@@ -53,3 +96,47 @@ func NewGetCommand() *cobra.Command {
 
 	return cmd
 }
+
+func getOutputs() []string {
+	return []string{"yaml", "json"}
+}
+
+// maskEngineManifest masks the otel config of every collector in the manifest.
+func maskEngineManifest(manifest []byte, output string) (string, error) {
+	engine := make(map[string]any)
+	if err := yaml.Unmarshal(manifest, &engine); err != nil {
+		return "", fmt.Errorf("failed to parse engine: %w", err)
+	}
+	spec, _ := engine["spec"].(map[string]any)
+	telemetryModule, _ := spec["telemetryModule"].(map[string]any)
+	collectors, _ := telemetryModule["collectors"].([]any)
+	for _, collector := range collectors {
+		c, _ := collector.(map[string]any)
+		collectorSpec, _ := c["spec"].(map[string]any)
+		otelConfig, ok := collectorSpec["config"].(string)
+		if !ok {
+			continue
+		}
+		cfg, err := otelconfig.Parse(otelConfig)
+		if err != nil {
+			return "", err
+		}
+		cfg.Mask()
+		if collectorSpec["config"], err = cfg.String(); err != nil {
+			return "", err
+		}
+	}
+
+	if output == "json" {
+		data, err := json.MarshalIndent(engine, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal engine: %w", err)
+		}
+		return string(data) + "\n", nil
+	}
+	data, err := yaml.Marshal(engine)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal engine: %w", err)
+	}
+	return string(data), nil
+}
@@ -2,4 +2,5 @@ package cmd
 
 type getFlags struct {
 	configType string
+	output     string
 }
@@ -17,6 +17,11 @@ func TestGetCommandErr(t *testing.T) {
 			args: []string{"get", "--config", "foo"},
 			err:  errors.New("config type foo is not supported"),
 		},
+		{
+			name: "get command with invalid output flag",
+			args: []string{"get", "--config", "mdai", "--output", "table"},
+			err:  errors.New("invalid output: table"),
+		},
 	}
 
 	errTests.Run(t)
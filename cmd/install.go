@@ -8,11 +8,13 @@ import (
 	"os"
 
 	"github.com/charmbracelet/huh"
-	"github.com/charmbracelet/huh/spinner"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	"github.com/decisiveai/mdai-cli/internal/config"
 	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
 	"github.com/decisiveai/mdai-cli/internal/operator"
+	"github.com/decisiveai/mdai-cli/internal/progress"
 	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
 	"github.com/spf13/cobra"
 )
@@ -24,14 +26,21 @@ func NewInstallCommand() *cobra.Command {
 	flags := installFlags{}
 	cmd := &cobra.Command{
 		GroupID: "installation",
-		Use:     "install [--cluster-name CLUSTER-NAME] [--debug] [--quiet]",
+		Use:     "install [--cluster-name CLUSTER-NAME] [--debug] [--quiet] [--timeout DURATION] [--retries N] [--chart-repo REPO]",
 		Short:   "install MyDecisive Cluster",
 		Long:    "install MyDecisive Cluster",
 		Example: `  mdai install --kubecontext kind-mdai-local # install on kind cluster mdai-local
   mdai install --debug                   # install in debug mode
   mdai install --quiet                   # install in quiet mode
-  mdai install --confirm                 # install, with confirmation`,
+  mdai install --confirm                 # install, with confirmation
+  mdai install --timeout 5m --retries 10 # install, waiting up to 5 minutes per step
+  mdai install --namespace team-mdai     # install into namespace team-mdai
+  mdai install --chart-repo oci://registry.example.com/charts # install charts from an OCI registry
+  mdai install --chart-repo ./charts     # install charts from a local directory`,
 		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateWaitFlags(flags.timeout, flags.retries)
+		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx := cmd.Context()
 			if !flags.confirm {
@@ -54,12 +63,22 @@ func NewInstallCommand() *cobra.Command {
 				logger.SetLevel(log.DebugLevel)
 			}
 			ctx = log.WithContext(ctx, logger)
+			ctx = context.WithValue(ctx, mdaitypes.Timeout{}, flags.timeout)
+			ctx = context.WithValue(ctx, mdaitypes.Retries{}, flags.retries)
+			if flags.chartRepo != "" {
+				ctx = context.WithValue(ctx, mdaitypes.ChartRepo{}, flags.chartRepo)
+			}
+			ctx = context.WithValue(ctx, mdaitypes.InsecureSkipVerify{}, flags.insecureSkipVerify)
 			return mdaiInstall(ctx)
 		},
 	}
 	cmd.Flags().BoolVar(&flags.debug, "debug", false, "debug mode")
 	cmd.Flags().BoolVar(&flags.quiet, "quiet", false, "quiet mode")
 	cmd.Flags().BoolVar(&flags.confirm, "confirm", false, "confirm installation")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", kubehelper.DefaultTimeout, "time to wait for each step to complete")
+	cmd.Flags().IntVar(&flags.retries, "retries", kubehelper.DefaultRetries, "number of attempts for transient errors")
+	cmd.Flags().StringVar(&flags.chartRepo, "chart-repo", "", chartRepoUsage)
+	cmd.Flags().BoolVar(&flags.insecureSkipVerify, "insecure-skip-verify", false, insecureSkipVerifyUsage)
 
 	cmd.MarkFlagsMutuallyExclusive("debug", "quiet")
 
@@ -70,25 +89,27 @@ func NewInstallCommand() *cobra.Command {
 }
 
 func mdaiInstall(ctx context.Context) error {
-	spinnerCtx, cancel := context.WithCancelCause(ctx)
+	helper, err := kubehelper.New(kubehelper.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+
+	installCtx, cancel := context.WithCancelCause(ctx)
 
 	go func() {
 		opts := []mdaihelm.ClientOption{mdaihelm.WithContext(ctx)}
 		helmclient := mdaihelm.NewClient(opts...)
 		cancel(helmclient.InstallChart("mdai-cluster"))
 	}()
-	if err := spinner.New().
-		Title("installing MDAI Cluster 🐙").
-		TitleStyle(lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00020A", Dark: "#D3D3D3"})).
-		Style(lipgloss.NewStyle().PaddingLeft(1).Foreground(purple)).
-		Context(spinnerCtx).
-		Run(); err != nil {
+
+	watcher := progress.NewWatcher(helper, helper.Namespace(), customResourceDefinitions())
+	if err := progress.Run(installCtx, watcher, "installing MDAI Cluster 🐙", ProgressStyles); err != nil {
 		return fmt.Errorf("failed to install cluster: %w", err)
 	}
 
-	if spinnerCtx.Err() != nil && !errors.Is(context.Cause(spinnerCtx), context.Canceled) {
+	if installCtx.Err() != nil && !errors.Is(context.Cause(installCtx), context.Canceled) {
 		fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(red).Render(DisabledString) + " installing MDAI Cluster 🐙")
-		return fmt.Errorf("failed to install cluster: %w", context.Cause(spinnerCtx))
+		return fmt.Errorf("failed to install cluster: %w", unverifiedHint(context.Cause(installCtx)))
 	}
 
 	fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(green).Render(EnabledString) + " installing MDAI Cluster 🐙")
@@ -98,6 +119,17 @@ func mdaiInstall(ctx context.Context) error {
 		return fmt.Errorf("failed to apply mdai operator manifest: %w", err)
 	}
 
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if profile := cfg.Profile(ctx.Value(mdaitypes.Profile{}).(string)); profile.Namespace != helper.Namespace() {
+		profile.Namespace = helper.Namespace()
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+	}
+
 	fmt.Println(" 🍻 You're ready to go")
 	return nil
 }
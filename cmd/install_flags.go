@@ -1,7 +1,13 @@
 package cmd
 
+import "time"
+
 type installFlags struct {
-	confirm bool
-	debug   bool
-	quiet   bool
+	confirm            bool
+	debug              bool
+	quiet              bool
+	timeout            time.Duration
+	retries            int
+	chartRepo          string
+	insecureSkipVerify bool
 }
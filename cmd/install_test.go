@@ -17,6 +17,16 @@ func TestInstallCommandErr(t *testing.T) {
 			args: []string{"install", "--debug", "--quiet"},
 			err:  errors.New(`if any flags in the group [debug quiet] are set none of the others can be; [debug quiet] were all set`),
 		},
+		{
+			name: "install with non-positive --timeout",
+			args: []string{"install", "--timeout", "0s"},
+			err:  errors.New("invalid timeout: 0s"),
+		},
+		{
+			name: "install with non-positive --retries",
+			args: []string{"install", "--retries", "0"},
+			err:  errors.New("invalid retries: 0"),
+		},
 	}
 
 	errTests.Run(t)
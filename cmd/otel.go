@@ -0,0 +1,576 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/decisiveai/mdai-cli/internal/operator"
+	"github.com/decisiveai/mdai-cli/internal/otelconfig"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func NewOtelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		GroupID: "configuration",
+		Use:     "otel",
+		Short:   "edit the otel collector config",
+		Long:    "add and remove receivers, processors, exporters and pipelines of the otel collector config, keeping its comments and ordering, draw its pipelines, lint it and render config templates",
+		Example: `  mdai otel exporter add otlphttp/vendor --set endpoint=https://otlp.vendor.com --pipelines traces,logs
+  mdai otel processor add batch --before exporters   # append batch to the processors of every pipeline
+  mdai otel processor add filter/pii --after memory_limiter --pipelines logs
+  mdai otel receiver remove hostmetrics               # remove a receiver and its pipeline references
+  mdai otel pipeline add logs/audit                   # add a pipeline with the components of logs
+  mdai otel pipeline remove logs/audit --dry-run      # print the config without applying it
+  mdai otel graph --format mermaid                    # draw the pipelines as a mermaid flowchart
+  mdai otel lint -f collector.yaml --output sarif     # lint a config file for code scanning
+  mdai otel render -f collector.tmpl --vars-file prod.yaml --var env=prod
+  mdai otel render -f base.yaml -f security.yaml      # print the merged fragments`,
+	}
+
+	cmd.AddCommand(
+		newOtelComponentCommand(otelconfig.Receivers),
+		newOtelComponentCommand(otelconfig.Processors),
+		newOtelComponentCommand(otelconfig.Exporters),
+		newOtelPipelineCommand(),
+		newOtelGraphCommand(),
+		newOtelLintCommand(),
+		newOtelRenderCommand(),
+	)
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newOtelComponentCommand(kind string) *cobra.Command {
+	name := strings.TrimSuffix(kind, "s")
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: "add or remove " + kind,
+	}
+
+	cmd.AddCommand(
+		newOtelComponentAddCommand(kind),
+		newOtelComponentRemoveCommand(kind),
+	)
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newOtelComponentAddCommand(kind string) *cobra.Command {
+	name := strings.TrimSuffix(kind, "s")
+	flags := otelAddFlags{}
+	use := "add ID [--set KEY=VALUE]... [--pipelines PIPELINE,...] [--dry-run]"
+	if kind == otelconfig.Processors {
+		use = "add ID [--set KEY=VALUE]... [--pipelines PIPELINE,...] [--before ID|exporters | --after ID|receivers] [--dry-run]"
+	}
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: "add " + name + " to the otel collector config",
+		Long:  "define a " + name + " with the --set values (dotted keys set nested values) and list it in the --pipelines, by default every pipeline of a signal it supports",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			position := otelconfig.PositionExporters
+			switch {
+			case flags.after == otelconfig.PositionReceivers:
+				position = otelconfig.PositionReceivers
+			case flags.after != "":
+				position = "after:" + flags.after
+			case flags.before != "" && flags.before != otelconfig.PositionExporters:
+				position = "before:" + flags.before
+			}
+
+			return mdaiOtelEdit(cmd.Context(), flags.dryRun, func(cfg *otelconfig.Config) (string, error) {
+				if err := cfg.AddComponent(kind, args[0], flags.set); err != nil {
+					return "", err
+				}
+				pipelines, err := otelPipelines(cfg, args[0], flags.pipelines)
+				if err != nil {
+					return "", err
+				}
+				for _, pipeline := range pipelines {
+					if err := cfg.AddToPipeline(pipeline, kind, args[0], position); err != nil {
+						return "", err
+					}
+				}
+				return fmt.Sprintf("%s %s added to pipelines %s", name, PurpleStyle.Render(args[0]), PurpleStyle.Render(strings.Join(pipelines, ", "))), nil
+			})
+		},
+	}
+	cmd.Flags().StringToStringVar(&flags.set, "set", nil, name+" setting, KEY=VALUE")
+	cmd.Flags().StringSliceVar(&flags.pipelines, "pipelines", nil, "pipelines to add the "+name+" to (default all of a signal it supports)")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "print the otel config instead of applying it")
+	if kind == otelconfig.Processors {
+		cmd.Flags().StringVar(&flags.before, "before", "", "processor to place it before, or exporters to place it last")
+		cmd.Flags().StringVar(&flags.after, "after", "", "processor to place it after, or receivers to place it first")
+		cmd.MarkFlagsMutuallyExclusive("before", "after")
+	}
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newOtelComponentRemoveCommand(kind string) *cobra.Command {
+	name := strings.TrimSuffix(kind, "s")
+	flags := otelRemoveFlags{}
+	cmd := &cobra.Command{
+		Use:   "remove ID [--dry-run]",
+		Short: "remove " + name + " from the otel collector config",
+		Long:  "remove a " + name + " and every pipeline reference to it; pipelines left without receivers or exporters fail validation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return mdaiOtelEdit(cmd.Context(), flags.dryRun, func(cfg *otelconfig.Config) (string, error) {
+				pipelines, err := cfg.RemoveComponent(kind, args[0])
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%s %s removed from pipelines %s", name, PurpleStyle.Render(args[0]), PurpleStyle.Render(strings.Join(pipelines, ", "))), nil
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "print the otel config instead of applying it")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newOtelPipelineCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pipeline",
+		Short: "add or remove pipelines",
+	}
+
+	cmd.AddCommand(
+		newOtelPipelineAddCommand(),
+		newOtelPipelineRemoveCommand(),
+	)
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newOtelPipelineAddCommand() *cobra.Command {
+	flags := otelPipelineFlags{}
+	cmd := &cobra.Command{
+		Use:   "add SIGNAL[/NAME] [--receivers ID,...] [--processors ID,...] [--exporters ID,...] [--dry-run]",
+		Short: "add a pipeline to the otel collector config",
+		Long:  "add a pipeline; without component flags it gets the components of the pipeline named after its signal",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return mdaiOtelEdit(cmd.Context(), flags.dryRun, func(cfg *otelconfig.Config) (string, error) {
+				components := map[string][]string{
+					otelconfig.Receivers:  flags.receivers,
+					otelconfig.Processors: flags.processors,
+					otelconfig.Exporters:  flags.exporters,
+				}
+				if len(flags.receivers)+len(flags.processors)+len(flags.exporters) == 0 {
+					signal, _, _ := strings.Cut(args[0], "/")
+					for _, kind := range otelconfig.Kinds() {
+						components[kind] = cfg.PipelineComponents(signal, kind)
+					}
+				}
+				if err := cfg.AddPipeline(args[0], components); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("pipeline %s added", PurpleStyle.Render(args[0])), nil
+			})
+		},
+	}
+	cmd.Flags().StringSliceVar(&flags.receivers, "receivers", nil, "receivers of the pipeline")
+	cmd.Flags().StringSliceVar(&flags.processors, "processors", nil, "processors of the pipeline, in order")
+	cmd.Flags().StringSliceVar(&flags.exporters, "exporters", nil, "exporters of the pipeline")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "print the otel config instead of applying it")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newOtelPipelineRemoveCommand() *cobra.Command {
+	flags := otelRemoveFlags{}
+	cmd := &cobra.Command{
+		Use:   "remove SIGNAL[/NAME] [--dry-run]",
+		Short: "remove a pipeline from the otel collector config",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return mdaiOtelEdit(cmd.Context(), flags.dryRun, func(cfg *otelconfig.Config) (string, error) {
+				if err := cfg.RemovePipeline(args[0]); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("pipeline %s removed", PurpleStyle.Render(args[0])), nil
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "print the otel config instead of applying it")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newOtelGraphCommand() *cobra.Command {
+	flags := otelGraphFlags{}
+	cmd := &cobra.Command{
+		Use:   "graph [--format FORMAT]",
+		Short: "draw the pipelines of the otel collector config",
+		Long:  "draw receivers -> processors -> exporters for every pipeline, annotating pipelines muted by enabled filters and highlighting unused components",
+		Args:  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if !slices.Contains(otelconfig.GraphFormats(), flags.format) {
+				return fmt.Errorf("invalid format: %s", flags.format)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			collector, err := operator.GetCollector(cmd.Context())
+			if err != nil {
+				return err
+			}
+			cfg, err := otelconfig.Parse(collector.Spec.Config)
+			if err != nil {
+				return err
+			}
+			muted := make(map[string][]string)
+			if collector.TelemetryFiltering != nil && collector.TelemetryFiltering.Filters != nil {
+				for _, filter := range *collector.TelemetryFiltering.Filters {
+					if !filter.Enabled || filter.MutedPipelines == nil {
+						continue
+					}
+					for _, pipeline := range *filter.MutedPipelines {
+						muted[pipeline] = append(muted[pipeline], filter.Name)
+					}
+				}
+			}
+
+			switch flags.format {
+			case otelconfig.GraphDOT:
+				fmt.Print(cfg.DOT(muted))
+			case otelconfig.GraphMermaid:
+				fmt.Print(cfg.Mermaid(muted))
+			default:
+				printOtelGraph(cfg, muted)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&flags.format, "format", otelconfig.GraphASCII, "output format ["+strings.Join(otelconfig.GraphFormats(), ", ")+"]")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func printOtelGraph(cfg *otelconfig.Config, muted map[string][]string) {
+	arrow := WhiteStyle.Render(" ─▶ ")
+	for _, pipeline := range cfg.Pipelines() {
+		header := PurpleStyle.Render(pipeline)
+		if len(muted[pipeline]) > 0 {
+			header += " " + DisabledStyle.Render("muted by "+strings.Join(muted[pipeline], ", "))
+		}
+		fmt.Println(header)
+
+		var steps []string
+		for _, kind := range otelconfig.Kinds() {
+			ids := cfg.PipelineComponents(pipeline, kind)
+			switch {
+			case len(ids) == 0:
+			case kind == otelconfig.Processors:
+				for _, id := range ids {
+					steps = append(steps, LightPurpleStyle.Render(id))
+				}
+			default:
+				steps = append(steps, "["+LightPurpleStyle.Render(strings.Join(ids, ", "))+"]")
+			}
+		}
+		fmt.Printf("  %s\n\n", strings.Join(steps, arrow))
+	}
+
+	var unused []string
+	for _, kind := range otelconfig.Kinds() {
+		for _, id := range cfg.Unused(kind) {
+			unused = append(unused, fmt.Sprintf("  %s %s", strings.TrimSuffix(kind, "s"), OutdatedStyle.Render(id)))
+		}
+	}
+	if len(unused) > 0 {
+		fmt.Println(OutdatedStyle.Render("unused"))
+		fmt.Println(strings.Join(unused, "\n"))
+	}
+}
+
+func newOtelLintCommand() *cobra.Command {
+	flags := otelLintFlags{}
+	cmd := &cobra.Command{
+		Use:   "lint [-f FILE] [--output FORMAT] [--disable RULE,...] [--rules]",
+		Short: "lint the otel collector config",
+		Long: `check the otel collector config, or a config file, for best-practice problems and fail on warnings;
+turn rules off with --disable or with a "# mdai-lint-disable RULE,..." comment on the yaml they report,
+or for the whole config with "# mdai-lint-disable-file RULE,..."`,
+		Args: cobra.NoArgs,
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+		},
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			flags.output = cmd.Context().Value(mdaitypes.Output{}).(string)
+			if !slices.Contains(otelLintOutputs(), flags.output) {
+				if cmd.Flags().Changed("output") {
+					return fmt.Errorf("invalid output: %s", flags.output)
+				}
+				flags.output = otelLintOutputs()[0]
+			}
+			for _, id := range flags.disable {
+				if !slices.ContainsFunc(otelconfig.Rules(), func(rule otelconfig.Rule) bool { return rule.ID == id }) {
+					return fmt.Errorf("invalid rule: %s", id)
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if flags.rules {
+				rows := make([][]string, 0, len(otelconfig.Rules()))
+				for _, rule := range otelconfig.Rules() {
+					rows = append(rows, []string{rule.ID, string(rule.Severity), rule.Description})
+				}
+				fmt.Println(styledTable().Headers("RULE", "SEVERITY", "DESCRIPTION").Rows(rows...))
+				return nil
+			}
+
+			var otelConfig, uri string
+			if flags.file != "" {
+				data, err := os.ReadFile(flags.file)
+				if err != nil {
+					return fmt.Errorf(`error reading file "%s": %w`, flags.file, err)
+				}
+				otelConfig, uri = string(data), flags.file
+			} else {
+				collector, err := operator.GetCollector(cmd.Context())
+				if err != nil {
+					return err
+				}
+				otelConfig, uri = collector.Spec.Config, collector.Name+".yaml"
+			}
+			cfg, err := otelconfig.Parse(otelConfig)
+			if err != nil {
+				return err
+			}
+			findings := cfg.Lint(flags.disable...)
+
+			switch flags.output {
+			case "json":
+				data, err := json.MarshalIndent(findings, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal findings: %w", err)
+				}
+				fmt.Println(string(data))
+			case "sarif":
+				data, err := otelconfig.SARIF(findings, uri)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+			default:
+				printOtelLint(findings)
+			}
+
+			problems := 0
+			for _, finding := range findings {
+				if finding.Severity != otelconfig.SeverityInfo {
+					problems++
+				}
+			}
+			if problems > 0 {
+				return fmt.Errorf("lint found %d problem(s)", problems)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&flags.file, "file", "f", "", "otel config file to lint instead of the collector config")
+	cmd.Flags().StringP("output", "o", "", "output format ["+strings.Join(otelLintOutputs(), ", ")+"] (default text)")
+	cmd.Flags().StringSliceVar(&flags.disable, "disable", nil, "rules to skip")
+	cmd.Flags().BoolVar(&flags.rules, "rules", false, "list the lint rules")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newOtelRenderCommand() *cobra.Command {
+	flags := otelRenderFlags{}
+	cmd := &cobra.Command{
+		Use:   "render -f FILE... [--var KEY=VALUE]... [--vars-file FILE]",
+		Short: "render an otel config template",
+		Long:  "render otel config templates, with the sprig functions and toYaml, merge the fragments and print the config mdai update -f would apply",
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			otelConfig, err := readOtelConfig(flags.files, flags.varsFile, flags.vars)
+			if err != nil {
+				return err
+			}
+			fmt.Print(otelConfig)
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&flags.files, "file", "f", nil, "otel config template or fragment, repeat to merge fragments in order")
+	cmd.Flags().StringArrayVar(&flags.vars, "var", nil, "template variable, KEY=VALUE with dotted keys for nested values")
+	cmd.Flags().StringVar(&flags.varsFile, "vars-file", "", "yaml file with template variables")
+
+	_ = cmd.MarkFlagRequired("file")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+// readOtelConfig renders .tmpl files, or all files when vars are given, then merges them.
+func readOtelConfig(files []string, varsFile string, pairs []string) (string, error) {
+	var vars map[string]any
+	if varsFile != "" || len(pairs) > 0 {
+		var err error
+		if vars, err = otelconfig.Vars(varsFile, pairs); err != nil {
+			return "", err
+		}
+	}
+	fragments := make([]otelconfig.Fragment, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf(`error reading file "%s": %w`, file, err)
+		}
+		fragment := otelconfig.Fragment{File: file, Data: string(data)}
+		if vars != nil || strings.HasSuffix(file, ".tmpl") {
+			if fragment.Data, err = otelconfig.Render(file, fragment.Data, vars); err != nil {
+				return "", err
+			}
+		}
+		fragments = append(fragments, fragment)
+	}
+	if len(fragments) == 1 {
+		return fragments[0].Data, nil
+	}
+	cfg, err := otelconfig.Merge(fragments)
+	if err != nil {
+		return "", err
+	}
+	return cfg.String()
+}
+
+func otelLintOutputs() []string {
+	return []string{"text", "json", "sarif"}
+}
+
+func printOtelLint(findings []otelconfig.Finding) {
+	if len(findings) == 0 {
+		fmt.Println("no problems found")
+		return
+	}
+	rows := make([][]string, 0, len(findings))
+	for _, finding := range findings {
+		rows = append(rows, []string{string(finding.Severity), finding.Rule, fmt.Sprintf("%s:%d", finding.Path, finding.Line), finding.Message})
+	}
+	fmt.Println(styledTable().Headers("SEVERITY", "RULE", "LOCATION", "MESSAGE").Rows(rows...))
+}
+
+// warnOtelLint prints the lint problems of an otel config without failing.
+func warnOtelLint(otelConfig string) {
+	cfg, err := otelconfig.Parse(otelConfig)
+	if err != nil {
+		return
+	}
+	problems := 0
+	for _, finding := range cfg.Lint() {
+		if finding.Severity == otelconfig.SeverityInfo {
+			continue
+		}
+		problems++
+		fmt.Printf("%s %s: %s\n", OutdatedStyle.Render(string(finding.Severity)), finding.Rule, finding.Message)
+	}
+	if problems > 0 {
+		fmt.Printf("see %s for details\n", PurpleStyle.Render("mdai otel lint"))
+	}
+}
+
+// mdaiOtelEdit validates the edited config before printing or applying it.
+func mdaiOtelEdit(ctx context.Context, dryRun bool, edit func(*otelconfig.Config) (string, error)) error {
+	collector, err := operator.GetCollector(ctx)
+	if err != nil {
+		return err
+	}
+	cfg, err := otelconfig.Parse(collector.Spec.Config)
+	if err != nil {
+		return err
+	}
+	message, err := edit(cfg)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid otel config, not applied:\n%w", err)
+	}
+	otelConfig, err := cfg.String()
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Print(otelConfig)
+		return nil
+	}
+	if err := operator.UpdateOTELConfig(ctx, otelConfig); err != nil {
+		return fmt.Errorf("error updating otel collector configuration: %w", err)
+	}
+	fmt.Println(message)
+	return nil
+}
+
+func validateOtelConfig(otelConfig string) error {
+	cfg, err := otelconfig.Parse(otelConfig)
+	if err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
+
+func otelPipelines(cfg *otelconfig.Config, id string, names []string) ([]string, error) {
+	pipelines := cfg.Pipelines()
+	if len(names) == 0 {
+		for _, name := range pipelines {
+			if signal, _, _ := strings.Cut(name, "/"); otelconfig.SupportsSignal(id, signal) {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no pipeline carries a signal %s supports, add one with mdai otel pipeline add", id)
+		}
+		return names, nil
+	}
+	for _, name := range names {
+		if !slices.Contains(pipelines, name) {
+			return nil, fmt.Errorf("pipeline %s not found, expected one of [%s]", name, strings.Join(pipelines, ", "))
+		}
+		if signal, _, _ := strings.Cut(name, "/"); !otelconfig.SupportsSignal(id, signal) {
+			return nil, fmt.Errorf("%s does not support %s, the signal of pipeline %s", id, signal, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+type otelAddFlags struct {
+	set       map[string]string
+	pipelines []string
+	before    string
+	after     string
+	dryRun    bool
+}
+
+type otelRemoveFlags struct {
+	dryRun bool
+}
+
+type otelPipelineFlags struct {
+	receivers  []string
+	processors []string
+	exporters  []string
+	dryRun     bool
+}
+
+type otelGraphFlags struct {
+	format string
+}
+
+type otelLintFlags struct {
+	file    string
+	output  string
+	disable []string
+	rules   bool
+}
+
+type otelRenderFlags struct {
+	files    []string
+	vars     []string
+	varsFile string
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/decisiveai/mdai-cli/internal/configure"
+	"github.com/decisiveai/mdai-cli/internal/otelconfig"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOtelCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "otel exporter add command without id",
+			args: []string{"otel", "exporter", "add"},
+			err:  errors.New("accepts 1 arg(s), received 0"),
+		},
+		{
+			name: "otel processor add command with before and after",
+			args: []string{"otel", "processor", "add", "batch", "--before", "exporters", "--after", "receivers"},
+			err:  errors.New("if any flags in the group [before after] are set none of the others can be; [after before] were all set"),
+		},
+		{
+			name: "otel exporter add command with before",
+			args: []string{"otel", "exporter", "add", "debug", "--before", "exporters"},
+			err:  errors.New("unknown flag: --before"),
+		},
+		{
+			name: "otel pipeline remove command with too many args",
+			args: []string{"otel", "pipeline", "remove", "logs", "traces"},
+			err:  errors.New("accepts 1 arg(s), received 2"),
+		},
+		{
+			name: "otel graph command with invalid format",
+			args: []string{"otel", "graph", "--format", "svg"},
+			err:  errors.New("invalid format: svg"),
+		},
+		{
+			name: "otel graph command with args",
+			args: []string{"otel", "graph", "logs"},
+			err:  errors.New(`unknown command "logs" for "mdai otel graph"`),
+		},
+		{
+			name: "otel lint command with invalid output",
+			args: []string{"otel", "lint", "-f", "testdata/lint-collector-config.yaml", "--output", "xml"},
+			err:  errors.New("invalid output: xml"),
+		},
+		{
+			name: "otel lint command with invalid rule",
+			args: []string{"otel", "lint", "-f", "testdata/lint-collector-config.yaml", "--disable", "no-such-rule"},
+			err:  errors.New("invalid rule: no-such-rule"),
+		},
+		{
+			name: "otel lint command with problems",
+			args: []string{"otel", "lint", "-f", "testdata/lint-collector-config.yaml", "--output", "json"},
+			err:  errors.New("lint found 5 problem(s)"),
+		},
+		{
+			name: "otel lint command with rules disabled",
+			args: []string{"otel", "lint", "-f", "testdata/lint-collector-config.yaml", "--disable", "insecure-receiver,missing-memory-limiter"},
+		},
+		{
+			name: "otel render command without file",
+			args: []string{"otel", "render"},
+			err:  errors.New(`required flag(s) "file" not set`),
+		},
+		{
+			name: "otel render command with invalid var",
+			args: []string{"otel", "render", "-f", "templates/collector-config.yaml", "--var", "env"},
+			err:  errors.New("invalid var env, expected KEY=VALUE"),
+		},
+	}
+
+	errTests.Run(t)
+}
+
+func TestDefaultsLintClean(t *testing.T) {
+	starter, err := embedFS.ReadFile("templates/collector-config.yaml")
+	require.NoError(t, err)
+	template, err := embedFS.ReadFile("templates/mdai-operator.yaml")
+	require.NoError(t, err)
+
+	configs := map[string]string{"starter collector": string(starter)}
+	for _, exporters := range [][]string{{"debug"}, configure.Exporters()} {
+		answers := newAnswers()
+		answers.Exporters = exporters
+		answers.OTLPEndpoint = "otel.example.com:4317"
+		engine, err := answers.Engine(template)
+		require.NoError(t, err)
+		var obj struct {
+			Spec struct {
+				TelemetryModule struct {
+					Collectors []struct {
+						Spec struct {
+							Config string `yaml:"config"`
+						} `yaml:"spec"`
+					} `yaml:"collectors"`
+				} `yaml:"telemetryModule"`
+			} `yaml:"spec"`
+		}
+		require.NoError(t, yaml.Unmarshal(engine.Manifest, &obj))
+		require.NotEmpty(t, obj.Spec.TelemetryModule.Collectors)
+		configs["engine with "+fmt.Sprint(exporters)] = obj.Spec.TelemetryModule.Collectors[0].Spec.Config
+	}
+
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := otelconfig.Parse(config)
+			require.NoError(t, err)
+			for _, finding := range cfg.Lint() {
+				require.Equal(t, otelconfig.SeverityInfo, finding.Severity, "%s: %s", finding.Path, finding.Message)
+			}
+		})
+	}
+}
@@ -15,7 +15,7 @@ func NewOutdatedCommand() *cobra.Command {
 		GroupID: "configuration",
 		Use:     "outdated",
 		Short:   "shows current and wanted versions of MDAI installation packages",
-		Long:    `shows current and wanted versions of MDAI installation packages`,
+		Long:    `shows current and wanted versions of MDAI installation packages: installed charts, the subcharts pinned by their Chart.lock and the container images they deploy`,
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx := cmd.Context()
@@ -42,7 +42,7 @@ func NewOutdatedCommand() *cobra.Command {
 						return OddRowStyle
 					}
 				}).
-				Headers("", "RELEASE", "CURRENT", "WANTED").
+				Headers("", "NAME", "TYPE", "CURRENT", "WANTED").
 				Rows(rows...)
 			fmt.Println(t)
 			fmt.Printf("kubeconfig: %s\nkubecontext: %s\n",
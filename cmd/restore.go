@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/charmbracelet/huh"
+	"github.com/decisiveai/mdai-cli/internal/backup"
+	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
+	"github.com/decisiveai/mdai-cli/internal/operator"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func NewRestoreCommand() *cobra.Command {
+	flags := restoreFlags{}
+	cmd := &cobra.Command{
+		GroupID: "installation",
+		Use:     "restore -f FILE [--confirm] [--force] [--skip-values]",
+		Short:   "restore MDAI configuration from a backup",
+		Long:    "reapply a backup taken with mdai backup to an installed MyDecisive Cluster",
+		Example: `  mdai restore -f mdai-prod.yaml               # restore engine and helm values
+  mdai restore -f mdai-prod.yaml --skip-values # restore engine only
+  mdai restore -f mdai-prod.yaml --force       # restore even if versions do not match`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			b, err := backup.Load(flags.file)
+			if err != nil {
+				return err
+			}
+
+			helmclient := mdaihelm.NewClient(mdaihelm.WithContext(ctx))
+			rel, err := helmclient.Release("mdai-cluster")
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				return errors.New("MDAI is not installed, run mdai install first")
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get mdai-cluster release: %w", err)
+			}
+
+			helper, err := kubehelper.New(kubehelper.WithContext(ctx))
+			if err != nil {
+				return fmt.Errorf("failed to initialize kubehelper: %w", err)
+			}
+			crd, err := helper.GetCRD(ctx, operator.EngineCRD)
+			if err != nil {
+				return fmt.Errorf("failed to get crd %s: %w", operator.EngineCRD, err)
+			}
+			servedVersions := make([]string, 0, len(crd.Spec.Versions))
+			for _, version := range crd.Spec.Versions {
+				if version.Served {
+					servedVersions = append(servedVersions, version.Name)
+				}
+			}
+			if err := b.Compatible(rel.Chart.Metadata.Version, servedVersions); err != nil {
+				if !flags.force {
+					return fmt.Errorf("backup is not compatible with this installation: %w (use --force to restore anyway)", err)
+				}
+				fmt.Println("warning: " + err.Error())
+			}
+
+			if !flags.confirm {
+				kubeconfig := ctx.Value(mdaitypes.Kubeconfig{}).(string)
+				kubecontext := ctx.Value(mdaitypes.Kubecontext{}).(string)
+				if err := huh.NewConfirm().
+					Title(fmt.Sprintf("Restore backup from %s?", b.CreatedAt.Format("2006-01-02 15:04:05 MST"))).
+					Description(fmt.Sprintf("engine: %s (%d filters)\nchart: %s %s\nkubeconfig: %s\nkubecontext: %s\n",
+						b.EngineName(), b.Filters(), b.Chart.Name, b.Chart.Version, kubeconfig, kubecontext)).
+					Affirmative("Yes!").
+					Negative("No.").
+					Value(&flags.confirm).Run(); err != nil {
+					return fmt.Errorf("restore failed: %w", err)
+				}
+			}
+			if !flags.confirm {
+				return errors.New("aborting restore")
+			}
+
+			if !flags.skipValues && !sameValues(rel.Config, b.Chart.Values) {
+				if err := helmclient.UpgradeValues("mdai-cluster", b.Chart.Values); err != nil {
+					return fmt.Errorf("failed to restore helm values: %w", err)
+				}
+				fmt.Printf("%s values restored\n", b.Chart.Name)
+			}
+
+			manifest, err := b.EngineManifest()
+			if err != nil {
+				return fmt.Errorf("failed to marshal engine: %w", err)
+			}
+			if err := operator.Install(ctx, manifest); err != nil {
+				return fmt.Errorf("failed to apply mdai operator manifest: %w", err)
+			}
+			fmt.Printf("engine %s (%d filters) restored from %s\n", PurpleStyle.Render(b.EngineName()), b.Filters(), PurpleStyle.Render(flags.file))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&flags.file, "file", "f", "", "backup file to restore")
+	cmd.Flags().BoolVar(&flags.confirm, "confirm", false, "confirm restore")
+	cmd.Flags().BoolVar(&flags.force, "force", false, "restore even if the backup is not compatible with the installation")
+	cmd.Flags().BoolVar(&flags.skipValues, "skip-values", false, "do not restore helm values")
+
+	_ = cmd.MarkFlagRequired("file")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+// sameValues compares values through json, so yaml and json numbers match.
+func sameValues(a, b map[string]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	normalize := func(values map[string]any) any {
+		data, err := json.Marshal(values)
+		if err != nil {
+			return nil
+		}
+		var normalized any
+		_ = json.Unmarshal(data, &normalized)
+		return normalized
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
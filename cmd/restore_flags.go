@@ -0,0 +1,8 @@
+package cmd
+
+type restoreFlags struct {
+	file       string
+	confirm    bool
+	force      bool
+	skipValues bool
+}
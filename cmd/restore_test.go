@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRestoreCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "restore command without file flag",
+			args: []string{"restore"},
+			err:  errors.New(`required flag(s) "file" not set`),
+		},
+	}
+
+	errTests.Run(t)
+}
+
+func TestSameValues(t *testing.T) {
+	var fromYaml map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte("replicas: 2\nports: [4317, 4318]\nname: mdai\n"), &fromYaml))
+	var fromJSON map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{"replicas": 2, "ports": [4317, 4318], "name": "mdai"}`), &fromJSON))
+
+	require.True(t, sameValues(fromJSON, fromYaml))
+	require.True(t, sameValues(nil, map[string]any{}))
+	fromJSON["replicas"] = 3.0
+	require.False(t, sameValues(fromJSON, fromYaml))
+}
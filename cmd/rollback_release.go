@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func NewRollbackReleaseCommand() *cobra.Command {
+	flags := rollbackReleaseFlags{}
+	cmd := &cobra.Command{
+		GroupID: "installation",
+		Use:     "rollback-release [REVISION] [--list] [--confirm]",
+		Short:   "roll back MyDecisive Cluster to a previous revision",
+		Long:    "roll back the mdai-cluster helm release to a revision from its history",
+		Example: `  mdai rollback-release --list    # list revisions of the mdai-cluster release
+  mdai rollback-release           # pick a revision to roll back to
+  mdai rollback-release 3         # roll back to revision 3
+  mdai rollback-release 3 --debug # roll back to revision 3 in debug mode`,
+		Args: func(_ *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("accepts at most 1 arg(s), received %d", len(args))
+			}
+			if len(args) == 0 {
+				return nil
+			}
+			if flags.list {
+				return errors.New("--list does not take a revision")
+			}
+			if revision, err := strconv.Atoi(args[0]); err != nil || revision < 1 {
+				return fmt.Errorf(`invalid revision "%s"`, args[0])
+			}
+			return nil
+		},
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateWaitFlags(flags.timeout, 1)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			logger := log.New(os.Stderr)
+			if flags.debug {
+				logger.SetLevel(log.DebugLevel)
+			}
+			ctx = log.WithContext(ctx, logger)
+			ctx = context.WithValue(ctx, mdaitypes.Timeout{}, flags.timeout)
+
+			helmclient := mdaihelm.NewClient(mdaihelm.WithContext(ctx))
+			history, err := helmclient.History("mdai-cluster")
+			if err != nil {
+				return fmt.Errorf("failed to get release history: %w", err)
+			}
+
+			if flags.list {
+				printReleaseHistory(history)
+				return nil
+			}
+
+			var revision int
+			if len(args) == 1 {
+				revision, _ = strconv.Atoi(args[0])
+			} else {
+				options := make([]huh.Option[int], 0, len(history))
+				for _, rel := range history {
+					options = append(options, huh.NewOption(releaseRevisionString(rel), rel.Version))
+				}
+				if err := huh.NewSelect[int]().
+					Title("Roll back MDAI Cluster to revision").
+					Options(options...).
+					Value(&revision).Run(); err != nil {
+					return fmt.Errorf("rollback failed: %w", err)
+				}
+			}
+
+			if !flags.confirm {
+				kubeconfig := ctx.Value(mdaitypes.Kubeconfig{}).(string)
+				kubecontext := ctx.Value(mdaitypes.Kubecontext{}).(string)
+				if err := huh.NewConfirm().
+					Title(fmt.Sprintf("Roll back MDAI Cluster to revision %d?", revision)).
+					Description(fmt.Sprintf("kubeconfig: %s\nkubecontext: %s\n", kubeconfig, kubecontext)).
+					Affirmative("Yes!").
+					Negative("No.").
+					Value(&flags.confirm).Run(); err != nil {
+					return fmt.Errorf("rollback failed: %w", err)
+				}
+			}
+			if !flags.confirm {
+				return errors.New("aborting rollback")
+			}
+			return mdaiRollback(ctx, revision)
+		},
+	}
+	cmd.Flags().BoolVar(&flags.debug, "debug", false, "debug mode")
+	cmd.Flags().BoolVar(&flags.list, "list", false, "list release revisions")
+	cmd.Flags().BoolVar(&flags.confirm, "confirm", false, "confirm rollback")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", kubehelper.DefaultTimeout, "time to wait for the rollback to complete")
+
+	cmd.MarkFlagsMutuallyExclusive("list", "confirm")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func mdaiRollback(ctx context.Context, revision int) error {
+	spinnerCtx, cancel := context.WithCancelCause(ctx)
+	title := fmt.Sprintf("rolling back MDAI Cluster to revision %d 🐙", revision)
+
+	go func() {
+		helmclient := mdaihelm.NewClient(mdaihelm.WithContext(ctx))
+		cancel(helmclient.RollbackChart("mdai-cluster", revision))
+	}()
+	if err := spinner.New().
+		Title(title).
+		TitleStyle(lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00020A", Dark: "#D3D3D3"})).
+		Style(lipgloss.NewStyle().PaddingLeft(1).Foreground(purple)).
+		Context(spinnerCtx).
+		Run(); err != nil {
+		return fmt.Errorf("failed to roll back cluster: %w", err)
+	}
+
+	if spinnerCtx.Err() != nil && !errors.Is(context.Cause(spinnerCtx), context.Canceled) {
+		fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(red).Render(DisabledString) + " " + title)
+		return fmt.Errorf("failed to roll back cluster: %w", context.Cause(spinnerCtx))
+	}
+
+	fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(green).Render(EnabledString) + " " + title)
+	return nil
+}
+
+func printReleaseHistory(history []*release.Release) {
+	t := styledTable().Headers(releaseHistoryHeaders()...)
+	for _, rel := range history {
+		t.Row(
+			strconv.Itoa(rel.Version),
+			rel.Info.LastDeployed.Format(time.RFC3339),
+			rel.Info.Status.String(),
+			rel.Chart.Metadata.Name+"-"+rel.Chart.Metadata.Version,
+			rel.Chart.Metadata.AppVersion,
+			rel.Info.Description,
+		)
+	}
+	fmt.Println(t)
+}
+
+func releaseRevisionString(rel *release.Release) string {
+	return fmt.Sprintf("%d: %s-%s (%s, %s)",
+		rel.Version,
+		rel.Chart.Metadata.Name,
+		rel.Chart.Metadata.Version,
+		rel.Info.Status,
+		rel.Info.LastDeployed.Format(time.RFC3339),
+	)
+}
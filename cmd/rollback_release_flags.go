@@ -0,0 +1,10 @@
+package cmd
+
+import "time"
+
+type rollbackReleaseFlags struct {
+	confirm bool
+	debug   bool
+	list    bool
+	timeout time.Duration
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRollbackReleaseCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "rollback-release command with too many args",
+			args: []string{"rollback-release", "1", "2"},
+			err:  errors.New("accepts at most 1 arg(s), received 2"),
+		},
+		{
+			name: "rollback-release command with invalid revision",
+			args: []string{"rollback-release", "foo"},
+			err:  errors.New(`invalid revision "foo"`),
+		},
+		{
+			name: "rollback-release command with zero revision",
+			args: []string{"rollback-release", "0"},
+			err:  errors.New(`invalid revision "0"`),
+		},
+		{
+			name: "rollback-release command with revision and list flag",
+			args: []string{"rollback-release", "2", "--list"},
+			err:  errors.New("--list does not take a revision"),
+		},
+		{
+			name: "rollback-release command with non-positive --timeout",
+			args: []string{"rollback-release", "--timeout", "0s"},
+			err:  errors.New("invalid timeout: 0s"),
+		},
+	}
+
+	errTests.Run(t)
+}
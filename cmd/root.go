@@ -2,15 +2,14 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
 const mdaiLogo = `
@@ -37,6 +36,9 @@ const mdaiLogo = `
   
 `
 
+// optionalKubeconfig marks commands that also work without a cluster.
+const optionalKubeconfig = "mdai.ai/optional-kubeconfig"
+
 var (
 	Version   = "development"
 	GitSha    = "development"
@@ -59,28 +61,36 @@ func NewRootCommand() (*cobra.Command, error) {
 		Short: "MyDecisive.ai CLI",
 		Long:  mdaiLogo,
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
-			kubeconfig := viper.GetString("kubeconfig")
-			kubecontext := viper.GetString("kubecontext")
-
-			if kubeconfig == "" {
-				if home := homedir.HomeDir(); home != "" {
-					kubeconfig = filepath.Join(home, ".kube", "config")
-				}
+			profile, settings, err := loadSettings(cmd)
+			if err != nil {
+				return err
 			}
+			kubeconfig := settings["kubeconfig"].value
+			kubecontext := settings["kubecontext"].value
+
+			_, optional := cmd.Annotations[optionalKubeconfig]
 			apiConfig, err := clientcmd.LoadFromFile(kubeconfig)
-			if err != nil {
+			switch {
+			case err != nil && !optional:
 				return fmt.Errorf("error loading kubeconfig: %w", err)
-			}
-			if kubecontext == "" {
-				kubecontext = apiConfig.CurrentContext
-			}
-			if _, exists := apiConfig.Contexts[kubecontext]; !exists {
-				return fmt.Errorf("context '%s' does not exist in kubeconfig `%s`", kubecontext, kubeconfig)
+			case err == nil:
+				if kubecontext == "" {
+					kubecontext = apiConfig.CurrentContext
+				}
+				if _, exists := apiConfig.Contexts[kubecontext]; !exists && !optional {
+					return fmt.Errorf("context '%s' does not exist in kubeconfig `%s`", kubecontext, kubeconfig)
+				}
 			}
 
 			ctx := context.Background()
+			ctx = context.WithValue(ctx, mdaitypes.Profile{}, profile)
 			ctx = context.WithValue(ctx, mdaitypes.Kubeconfig{}, kubeconfig)
 			ctx = context.WithValue(ctx, mdaitypes.Kubecontext{}, kubecontext)
+			ctx = context.WithValue(ctx, mdaitypes.Namespace{}, settings["namespace"].value)
+			ctx = context.WithValue(ctx, mdaitypes.Engine{}, settings["engine"].value)
+			ctx = context.WithValue(ctx, mdaitypes.Collector{}, settings["collector"].value)
+			ctx = context.WithValue(ctx, mdaitypes.Output{}, settings["output"].value)
+			ctx = context.WithValue(ctx, mdaitypes.ChartRepo{}, settings["chart-repo"].value)
 			cmd.SetContext(ctx)
 			return nil
 		},
@@ -90,17 +100,27 @@ func NewRootCommand() (*cobra.Command, error) {
 	addGroups(cmd)
 	addCommands(cmd)
 
-	viper.AutomaticEnv()
-
+	cmd.PersistentFlags().String("profile", "", "Profile from ~/.mdai/config.yaml to use")
+	_ = viper.BindPFlag("profile", cmd.PersistentFlags().Lookup("profile"))
 	cmd.PersistentFlags().String("kubeconfig", "", "Path to a kubeconfig")
 	_ = viper.BindPFlag("kubeconfig", cmd.PersistentFlags().Lookup("kubeconfig"))
 	cmd.PersistentFlags().String("kubecontext", "", "Kubernetes context to use")
 	_ = viper.BindPFlag("kubecontext", cmd.PersistentFlags().Lookup("kubecontext"))
+	cmd.PersistentFlags().String("namespace", "", "Kubernetes namespace MDAI is installed in")
+	_ = viper.BindPFlag("namespace", cmd.PersistentFlags().Lookup("namespace"))
+	cmd.PersistentFlags().String("engine", "", "MyDecisiveEngine to configure, required when the namespace has several")
+	_ = viper.BindPFlag("engine", cmd.PersistentFlags().Lookup("engine"))
+	cmd.PersistentFlags().String("collector", "", "Collector of the engine to configure, defaults to the first one")
+	_ = viper.BindPFlag("collector", cmd.PersistentFlags().Lookup("collector"))
+	bindSettingEnvs()
 
 	cmd.SilenceUsage = true
 	cmd.DisableFlagsInUseLine = true
 
-	err := cmd.RegisterFlagCompletionFunc("kubecontext", kubecontextFlagCompletionFunc)
+	err := errors.Join(
+		cmd.RegisterFlagCompletionFunc("kubecontext", kubecontextFlagCompletionFunc),
+		cmd.RegisterFlagCompletionFunc("profile", profileCompletionFunc),
+	)
 
 	return cmd, err
 }
@@ -114,20 +134,29 @@ func addGroups(cmd *cobra.Command) {
 
 func addCommands(cmd *cobra.Command) {
 	cmd.AddCommand(
+		NewBackupCommand(),
+		NewCacheCommand(),
+		NewConfigCommand(),
 		NewConfigureCommand(),
 		NewCreateCommand(),
 		NewDeleteCommand(),
+		NewDemoCommand(),
 		NewDisableCommand(),
 		NewDocsCommand(),
 		NewEnableCommand(),
 		NewFilterCommand(),
 		NewGetCommand(),
 		NewInstallCommand(),
+		NewOtelCommand(),
 		NewOutdatedCommand(),
-		NewRemoveCommand(),
+		NewRestoreCommand(),
+		NewRollbackReleaseCommand(),
+		NewSecretCommand(),
+		NewSelfUpdateCommand(),
 		NewStatusCommand(),
 		NewUninstallCommand(),
 		NewUpdateCommand(),
+		NewVersionCommand(),
 	)
 }
 
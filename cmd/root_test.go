@@ -2,11 +2,63 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: kind-mdai-local
+  cluster:
+    server: https://127.0.0.1:1
+contexts:
+- name: kind-mdai-local
+  context:
+    cluster: kind-mdai-local
+    user: kind-mdai-local
+current-context: kind-mdai-local
+users:
+- name: kind-mdai-local
+  user:
+    token: test
+`
+
+// TestMain keeps the developer's ~/.mdai/config.yaml, kubeconfig and MDAI_*
+// variables out of the tests.
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "mdai-test-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	code := func() int {
+		defer os.RemoveAll(home)
+		if err := os.MkdirAll(filepath.Join(home, ".kube"), 0o700); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if err := os.WriteFile(filepath.Join(home, ".kube", "config"), []byte(testKubeconfig), 0o600); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		os.Setenv("HOME", home)
+		for _, env := range os.Environ() {
+			key, _, _ := strings.Cut(env, "=")
+			if strings.HasPrefix(key, "MDAI_") || key == "KUBECONFIG" || key == "KUBECONTEXT" {
+				os.Unsetenv(key)
+			}
+		}
+		return m.Run()
+	}()
+	os.Exit(code)
+}
+
 type testCmdErr struct {
 	name   string
 	args   []string
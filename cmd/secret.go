@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/decisiveai/mdai-cli/internal/operator"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func NewSecretCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		GroupID: "configuration",
+		Use:     "secret",
+		Short:   "manage exporter credentials",
+		Long:    "keep exporter credentials in kubernetes secrets exposed to the collector as env variables, instead of inline in the otel config",
+		Example: `  mdai secret set vendor-api-key --from-literal s3cr3t   # reference it as ${env:VENDOR_API_KEY}
+  mdai secret set vendor-api-key --from-file key.txt
+  mdai secret set vendor-token --from-file token.txt --env VENDOR_TOKEN`,
+	}
+
+	cmd.AddCommand(
+		newSecretSetCommand(),
+	)
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func newSecretSetCommand() *cobra.Command {
+	flags := secretSetFlags{}
+	cmd := &cobra.Command{
+		Use:   "set NAME (--from-literal VALUE | --from-file FILE) [--env ENV]",
+		Short: "create or update a secret and expose it to the collector",
+		Long:  "create or update the secret NAME in the mdai namespace and add it to the collector env as ENV, by default NAME in upper snake case",
+		Args:  cobra.ExactArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if errs := validation.IsDNS1123Subdomain(args[0]); len(errs) > 0 {
+				return fmt.Errorf("invalid secret name: %s, %s", args[0], strings.Join(errs, ", "))
+			}
+			if flags.env == "" {
+				flags.env = strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(args[0]))
+			}
+			if errs := validation.IsEnvVarName(flags.env); len(errs) > 0 {
+				return fmt.Errorf("invalid env: %s, %s", flags.env, strings.Join(errs, ", "))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value := []byte(flags.literal)
+			if flags.file != "" {
+				var err error
+				if value, err = os.ReadFile(flags.file); err != nil {
+					return fmt.Errorf(`error reading file "%s": %w`, flags.file, err)
+				}
+			}
+			if err := operator.SetSecret(cmd.Context(), args[0], flags.env, value); err != nil {
+				return err
+			}
+			namespace := cmd.Context().Value(mdaitypes.Namespace{}).(string)
+			fmt.Printf("secret %s set in namespace %s, reference it in the otel config as %s\n",
+				PurpleStyle.Render(args[0]), PurpleStyle.Render(namespace), PurpleStyle.Render("${env:"+flags.env+"}"))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&flags.literal, "from-literal", "", "secret value")
+	cmd.Flags().StringVar(&flags.file, "from-file", "", "file with the secret value")
+	cmd.Flags().StringVar(&flags.env, "env", "", "collector env variable for the secret")
+
+	cmd.MarkFlagsMutuallyExclusive("from-literal", "from-file")
+	cmd.MarkFlagsOneRequired("from-literal", "from-file")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
@@ -0,0 +1,7 @@
+package cmd
+
+type secretSetFlags struct {
+	literal string
+	file    string
+	env     string
+}
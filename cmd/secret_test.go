@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSecretCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "secret set command without value",
+			args: []string{"secret", "set", "vendor-api-key"},
+			err:  errors.New("at least one of the flags in the group [from-literal from-file] is required"),
+		},
+		{
+			name: "secret set command with literal and file",
+			args: []string{"secret", "set", "vendor-api-key", "--from-literal", "abc", "--from-file", "key.txt"},
+			err:  errors.New("if any flags in the group [from-literal from-file] are set none of the others can be; [from-file from-literal] were all set"),
+		},
+		{
+			name: "secret set command with invalid env",
+			args: []string{"secret", "set", "vendor-api-key", "--from-literal", "abc", "--env", "1KEY"},
+			err:  errors.New("invalid env: 1KEY, a valid environment variable name must consist of alphabetic characters, digits, '_', '-', or '.', and must not start with a digit (e.g. 'my.env-name',  or 'MY_ENV.NAME',  or 'MyEnvName1', regex used for validation is '[-._a-zA-Z][-._a-zA-Z0-9]*')"),
+		},
+	}
+
+	errTests.Run(t)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/decisiveai/mdai-cli/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+func NewSelfUpdateCommand() *cobra.Command {
+	flags := selfUpdateFlags{}
+	cmd := &cobra.Command{
+		GroupID: "installation",
+		Use:     "self-update [--url URL] [--check] [--force] [--confirm] [--insecure-skip-verify]",
+		Short:   "update mdai to the latest release",
+		Long:    "download the latest mdai release, verify its signed manifest, its checksum and that it runs, and replace the running binary with it",
+		Example: `  mdai self-update                               # update to the latest release
+  mdai self-update --check                       # only report whether an update is available
+  mdai self-update --url http://localhost:8080   # update from a local release mirror
+  MDAI_UPDATE_URL=http://localhost:8080 mdai self-update --confirm`,
+		Args: cobra.NoArgs,
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if !cmd.Flags().Changed("url") {
+				if url := os.Getenv("MDAI_UPDATE_URL"); url != "" {
+					flags.url = url
+				}
+			}
+
+			release, err := selfupdate.Latest(ctx, flags.url)
+			if err != nil {
+				return fmt.Errorf("failed to check for updates: %w", err)
+			}
+			newer := release.Newer(Version)
+			switch {
+			case !newer && !flags.force:
+				fmt.Printf("mdai %s is up to date\n", Version)
+				return nil
+			case flags.check:
+				fmt.Printf("mdai %s is available (installed: %s)\n", PurpleStyle.Render(release.Version), Version)
+				return nil
+			}
+
+			if !flags.confirm {
+				if err := huh.NewConfirm().
+					Title(fmt.Sprintf("Update mdai %s to %s?", Version, release.Version)).
+					Description("from " + flags.url).
+					Affirmative("Yes!").
+					Negative("No.").
+					Value(&flags.confirm).Run(); err != nil {
+					return fmt.Errorf("self-update failed: %w", err)
+				}
+			}
+			if !flags.confirm {
+				return errors.New("aborting self-update")
+			}
+			return mdaiSelfUpdate(ctx, release, flags.insecureSkipVerify)
+		},
+	}
+	cmd.Flags().StringVar(&flags.url, "url", selfupdate.DefaultURL, "base URL of the release to update from, or set MDAI_UPDATE_URL")
+	cmd.Flags().BoolVar(&flags.check, "check", false, "only report whether an update is available")
+	cmd.Flags().BoolVar(&flags.force, "force", false, "reinstall even if mdai is up to date")
+	cmd.Flags().BoolVar(&flags.confirm, "confirm", false, "confirm update")
+	cmd.Flags().BoolVar(&flags.insecureSkipVerify, "insecure-skip-verify", false, "update without verifying the release manifest signature")
+
+	cmd.MarkFlagsMutuallyExclusive("check", "force")
+	cmd.MarkFlagsMutuallyExclusive("check", "confirm")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func mdaiSelfUpdate(ctx context.Context, release *selfupdate.Release, insecureSkipVerify bool) error {
+	var binary string
+	spinnerCtx, cancel := context.WithCancelCause(ctx)
+	title := fmt.Sprintf("downloading mdai %s 🐙", release.Version)
+
+	go func() {
+		var err error
+		binary, err = release.Download(ctx, insecureSkipVerify)
+		cancel(err)
+	}()
+	if err := spinner.New().
+		Title(title).
+		TitleStyle(lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00020A", Dark: "#D3D3D3"})).
+		Style(lipgloss.NewStyle().PaddingLeft(1).Foreground(purple)).
+		Context(spinnerCtx).
+		Run(); err != nil {
+		return fmt.Errorf("failed to update mdai: %w", err)
+	}
+
+	if spinnerCtx.Err() != nil && !errors.Is(context.Cause(spinnerCtx), context.Canceled) {
+		fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(red).Render(DisabledString) + " " + title)
+		err := context.Cause(spinnerCtx)
+		if errors.Is(err, selfupdate.ErrUnverified) {
+			err = fmt.Errorf("%w (use --insecure-skip-verify to update anyway)", err)
+		}
+		return fmt.Errorf("failed to update mdai: %w", err)
+	}
+	fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(green).Render(EnabledString) + " " + title)
+	defer os.Remove(binary)
+
+	executable, err := selfupdate.Replace(binary)
+	if err != nil {
+		return fmt.Errorf("failed to update mdai: %w", err)
+	}
+	fmt.Printf(" 🍻 %s updated to %s\n", PurpleStyle.Render(executable), PurpleStyle.Render(release.Version))
+	return nil
+}
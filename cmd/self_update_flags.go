@@ -0,0 +1,9 @@
+package cmd
+
+type selfUpdateFlags struct {
+	url                string
+	check              bool
+	force              bool
+	confirm            bool
+	insecureSkipVerify bool
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelfUpdateCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "self-update command with args",
+			args: []string{"self-update", "v1.0.0"},
+			err:  errors.New(`unknown command "v1.0.0" for "mdai self-update"`),
+		},
+		{
+			name: "self-update with both --check and --force",
+			args: []string{"self-update", "--check", "--force"},
+			err:  errors.New(`if any flags in the group [check force] are set none of the others can be; [check force] were all set`),
+		},
+	}
+
+	errTests.Run(t)
+}
@@ -1,34 +1,21 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"github.com/decisiveai/mdai-cli/internal/discovery"
 	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
 	"github.com/decisiveai/mdai-cli/internal/kubehelper"
 	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
 	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/storage/driver"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-type deployment struct {
-	name      string
-	namespace string
-}
-
-var deployments = []deployment{
-	{name: "datalyzer-deployment", namespace: "mdai"},
-	{name: "mdai-console", namespace: "mdai"},
-	{name: "prometheus-server", namespace: "mdai"},
-	{name: "prometheus-kube-state-metrics", namespace: "mdai"},
-	{name: "gateway-collector", namespace: "mdai"},
-	{name: "mydecisive-engine-operator-controller-manager", namespace: "mdai"},
-	{name: "opentelemetry-operator", namespace: "mdai"},
-}
-
 func NewStatusCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status",
@@ -60,45 +47,58 @@ func NewStatusCommand() *cobra.Command {
 			}
 
 			fmt.Println(t)
-			fmt.Printf("kubeconfig: %s\nkubecontext: %s\n",
+			fmt.Printf("kubeconfig: %s\nkubecontext: %s\nnamespace: %s\n",
 				PurpleStyle.Render(ctx.Value(mdaitypes.Kubeconfig{}).(string)),
 				PurpleStyle.Render(ctx.Value(mdaitypes.Kubecontext{}).(string)),
+				PurpleStyle.Render(ctx.Value(mdaitypes.Namespace{}).(string)),
 			)
+			helper, err := kubehelper.New(kubehelper.WithContext(ctx))
+			if err != nil {
+				return fmt.Errorf("failed creating kubehelper: %w", err)
+			}
+			resources, err := helmclient.ReleaseResources("mdai-cluster")
+			if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+				return fmt.Errorf("failed to get release resources: %w", err)
+			}
+			workloads, err := discovery.Workloads(ctx, helper, resources)
+			if err != nil {
+				return fmt.Errorf("failed to discover components: %w", err)
+			}
 
-			for _, deployment := range deployments {
-				helper, err := kubehelper.New(kubehelper.WithContext(ctx))
-				if err != nil {
-					return fmt.Errorf("failed creating kubehelper: %w", err)
-				}
-				d, err := helper.GetDeployment(ctx, deployment.name, deployment.namespace)
-				if err != nil {
+			for _, workload := range workloads {
+				release, version := workload.Chart()
+				if !workload.Found {
+					fmt.Printf("%s: %s (%s) [%s] %s\n",
+						workload.Kind,
+						LightPurpleStyle.Render(workload.Name),
+						PurpleStyle.Render(release),
+						PurpleStyle.Render(version),
+						lipgloss.NewStyle().Foreground(red).Render("missing"),
+					)
 					continue
 				}
-				labelSelector := metav1.FormatLabelSelector(d.Spec.Selector)
-				var release, version string
-				if _, ok := d.Labels["helm.sh/chart"]; ok {
-					lastIndex := strings.LastIndex(d.Labels["helm.sh/chart"], "-")
-					release = d.Labels["helm.sh/chart"][:lastIndex]
-					version = d.Labels["helm.sh/chart"][lastIndex+1:]
-				}
-				fmt.Printf("Deployment: %s (%s) [%s]\n",
-					LightPurpleStyle.Render(deployment.name),
+				fmt.Printf("%s: %s (%s) [%s] %d/%d ready\n",
+					workload.Kind,
+					LightPurpleStyle.Render(workload.Name),
 					PurpleStyle.Render(release),
 					PurpleStyle.Render(version),
+					workload.Ready,
+					workload.Desired,
 				)
 
-				pod, err := helper.GetPodByLabel(ctx, deployment.namespace, labelSelector)
+				labelSelector := metav1.FormatLabelSelector(workload.Selector)
+				pod, err := helper.GetPodByLabel(ctx, workload.Namespace, labelSelector)
 				if err != nil {
 					continue
 				}
 				for _, p := range pod.Items {
 					fmt.Printf("  Pod: %s\n", WhiteStyle.Render(p.Name))
 					for _, containerStatus := range p.Status.ContainerStatuses {
-						image := containerStatus.Image
-						lastPullTime := containerStatus.State.Running.StartedAt.Time
 						fmt.Printf("    Container: %s\n", WhiteStyle.Render(containerStatus.Name))
-						fmt.Printf("      Image: %s\n", WhiteStyle.Render(image))
-						fmt.Printf("      Last Pull: %s\n", WhiteStyle.Render(lastPullTime.Format(time.RFC3339)))
+						fmt.Printf("      Image: %s\n", WhiteStyle.Render(containerStatus.Image))
+						if containerStatus.State.Running != nil {
+							fmt.Printf("      Last Pull: %s\n", WhiteStyle.Render(containerStatus.State.Running.StartedAt.Format(time.RFC3339)))
+						}
 					}
 				}
 			}
@@ -1,6 +1,9 @@
 package cmd
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/decisiveai/mdai-cli/internal/progress"
+)
 
 const (
 	purple      = lipgloss.Color("#BF40BF")
@@ -25,4 +28,12 @@ var (
 	PurpleStyle      = lipgloss.NewStyle().Foreground(purple)
 	LightPurpleStyle = lipgloss.NewStyle().Foreground(lightPurple)
 	WhiteStyle       = lipgloss.NewStyle().Foreground(white)
+
+	ProgressStyles = progress.Styles{
+		Title:   lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#00020A", Dark: "#D3D3D3"}),
+		Spinner: lipgloss.NewStyle().PaddingLeft(1).Foreground(purple),
+		Ready:   lipgloss.NewStyle().PaddingLeft(3).Foreground(green),
+		Pending: lipgloss.NewStyle().PaddingLeft(3).Foreground(gray),
+		Status:  lipgloss.NewStyle().Foreground(gray),
+	}
 )
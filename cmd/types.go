@@ -1,5 +1,17 @@
 package cmd
 
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
+)
+
+const chartRepoUsage = "chart source to install from: an oci:// registry, a helm repository URL, or a local directory or .tgz, which is trusted without verification (OCI credentials are read from the helm registry config)"
+
+const insecureSkipVerifyUsage = "install charts without verifying their digest or provenance"
+
 const (
 	DisabledString = "✗"
 	EnabledString  = "✓"
@@ -15,7 +27,7 @@ func supportedGetConfigTypes() []string {
 }
 
 func supportedUpdateConfigTypes() []string {
-	return []string{"otel"}
+	return []string{"mdai", "otel"}
 }
 
 func supportedPhases() []string {
@@ -43,3 +55,48 @@ func pipelineFilterHeaders() []string {
 func filterServiceHeaders() []string {
 	return []string{"NAME", "DESCRIPTION", "ENABLED", "FILTERED PIPELINES", "FILTERED TELEMETRY", "SERVICE PATTERN"}
 }
+
+func releaseHistoryHeaders() []string {
+	return []string{"REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION", "DESCRIPTION"}
+}
+
+func validateWaitFlags(timeout time.Duration, retries int) error {
+	switch {
+	case timeout <= 0:
+		return fmt.Errorf("invalid timeout: %s", timeout)
+	case retries < 1:
+		return fmt.Errorf("invalid retries: %d", retries)
+	}
+	return nil
+}
+
+func demoComponentHeaders() []string {
+	return []string{"COMPONENT", "READY", "STATUS"}
+}
+
+func demoLoadHeaders() []string {
+	return []string{"JOB", "SIGNAL", "ACTIVE", "SUCCEEDED", "FAILED", "STARTED"}
+}
+
+func unverifiedHint(err error) error {
+	if errors.Is(err, mdaihelm.ErrUnverified) {
+		return fmt.Errorf("%w (use --insecure-skip-verify to install anyway)", err)
+	}
+	return err
+}
+
+func cacheHeaders() []string {
+	return []string{"CHART", "VERSION", "DIGEST", "SOURCE", "SIZE", "CACHED", "IN USE"}
+}
+
+func versionCheckHeaders() []string {
+	return []string{"COMPONENT", "INSTALLED", "SUPPORTED", "OK"}
+}
+
+func configProfileHeaders() []string {
+	return []string{"", "NAME", "KUBECONTEXT", "NAMESPACE", "ENGINE", "COLLECTOR"}
+}
+
+func configViewHeaders() []string {
+	return []string{"KEY", "VALUE", "SOURCE"}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/huh/spinner"
@@ -14,23 +16,46 @@ import (
 	"github.com/decisiveai/mdai-cli/internal/kubehelper"
 	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
 	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func NewUninstallCommand() *cobra.Command {
 	flags := uninstallFlags{}
 	cmd := &cobra.Command{
 		GroupID: "installation",
-		Use:     "uninstall",
+		Use:     "uninstall [--dry-run] [--keep-crds|--force]",
 		Short:   "uninstall MyDecisive Cluster",
 		Long:    "uninstall MyDecisive Cluster",
 		Example: `  mdai uninstall --kubecontext kind-mdai-local # uninstall from kind cluster mdai-local
   mdai uninstall --debug                   # uninstall in debug mode
   mdai uninstall --quiet                   # uninstall in quiet mode
-  mdai uninstall --confirm                 # uninstall, with confirmation`,
+  mdai uninstall --confirm                 # uninstall, with confirmation
+  mdai uninstall --timeout 5m              # uninstall, waiting up to 5 minutes
+  mdai uninstall --dry-run                 # list what would be removed
+  mdai uninstall --keep-crds               # uninstall, keeping custom resource definitions
+  mdai uninstall --backup mdai-prod.yaml   # back up configuration, then uninstall`,
 		Args: cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateWaitFlags(flags.timeout, flags.retries)
+		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx := cmd.Context()
+			logger := log.New(os.Stderr)
+			if flags.debug {
+				logger.SetLevel(log.DebugLevel)
+			}
+			ctx = log.WithContext(ctx, logger)
+			ctx = context.WithValue(ctx, mdaitypes.Timeout{}, flags.timeout)
+			ctx = context.WithValue(ctx, mdaitypes.Retries{}, flags.retries)
 
+			if flags.dryRun {
+				return mdaiUninstallPreview(ctx, flags)
+			}
+
+			takeBackup := flags.backup != ""
 			if !flags.confirm {
 				kubeconfig := ctx.Value(mdaitypes.Kubeconfig{}).(string)
 				kubecontext := ctx.Value(mdaitypes.Kubecontext{}).(string)
@@ -42,28 +67,65 @@ func NewUninstallCommand() *cobra.Command {
 					Value(&flags.confirm).Run(); err != nil {
 					return fmt.Errorf("uninstall failed: %w", err)
 				}
+				if flags.confirm && !takeBackup {
+					takeBackup = true
+					if err := huh.NewConfirm().
+						Title("Back up MDAI configuration first?").
+						Description("filters, otel collector configuration and helm values are lost on uninstall").
+						Affirmative("Yes!").
+						Negative("No.").
+						Value(&takeBackup).Run(); err != nil {
+						return fmt.Errorf("uninstall failed: %w", err)
+					}
+				}
 			}
 			if !flags.confirm {
 				return errors.New("aborting uninstallation")
 			}
-			logger := log.New(os.Stderr)
-			if flags.debug {
-				logger.SetLevel(log.DebugLevel)
+			if takeBackup {
+				if err := mdaiBackup(ctx, flags.backup); err != nil {
+					return fmt.Errorf("aborting uninstallation, backup failed: %w", err)
+				}
 			}
-			ctx = log.WithContext(ctx, logger)
-			return mdaiUninstall(ctx)
+			return mdaiUninstall(ctx, flags)
 		},
 	}
 	cmd.Flags().BoolVar(&flags.debug, "debug", false, "debug mode")
 	cmd.Flags().BoolVar(&flags.quiet, "quiet", false, "quiet mode")
 	cmd.Flags().BoolVar(&flags.confirm, "confirm", false, "confirm uninstallation")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", kubehelper.DefaultTimeout, "time to wait for each step to complete")
+	cmd.Flags().IntVar(&flags.retries, "retries", kubehelper.DefaultRetries, "number of attempts for transient errors")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "list what would be removed without removing anything")
+	cmd.Flags().BoolVar(&flags.keepCRDs, "keep-crds", false, "keep custom resource definitions and their instances")
+	cmd.Flags().StringVar(&flags.backup, "backup", "", "back up MDAI configuration to this file before uninstalling")
+	cmd.Flags().BoolVar(&flags.force, "force", false, "delete custom resource definitions even if they have instances outside the uninstalled namespace")
 
 	cmd.MarkFlagsMutuallyExclusive("debug", "quiet")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "confirm")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "backup")
+	cmd.MarkFlagsMutuallyExclusive("keep-crds", "force")
 
 	return cmd
 }
 
-func mdaiUninstall(ctx context.Context) error {
+func mdaiUninstall(ctx context.Context, flags uninstallFlags) error {
+	helper, err := kubehelper.New(kubehelper.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+
+	var removals []crdRemoval
+	if !flags.keepCRDs {
+		if removals, err = crdRemovals(ctx, helper); err != nil {
+			return err
+		}
+		if !flags.force {
+			for _, removal := range removals {
+				printForeignInstances(removal, helper.Namespace())
+			}
+		}
+	}
+
 	spinnerCtx, cancel := context.WithCancelCause(ctx)
 
 	go func() {
@@ -77,27 +139,186 @@ func mdaiUninstall(ctx context.Context) error {
 		Style(lipgloss.NewStyle().PaddingLeft(1).Foreground(purple)).
 		Context(spinnerCtx).
 		Run(); err != nil {
-		return fmt.Errorf("failed to install cluster: %w", err)
+		return fmt.Errorf("failed to uninstall cluster: %w", err)
 	}
 
 	if spinnerCtx.Err() != nil && !errors.Is(context.Cause(spinnerCtx), context.Canceled) {
 		fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(red).Render(DisabledString) + " uninstalling MDAI Cluster 🐙")
-		return fmt.Errorf("failed to install cluster: %w", context.Cause(spinnerCtx))
+		return fmt.Errorf("failed to uninstall cluster: %w", context.Cause(spinnerCtx))
 	}
 
 	fmt.Println(lipgloss.NewStyle().PaddingLeft(1).Foreground(green).Render(EnabledString) + " uninstalling MDAI Cluster 🐙")
+
+	if flags.keepCRDs {
+		fmt.Println("\tCRDs kept.")
+	}
+	for _, removal := range removals {
+		if !removal.found {
+			fmt.Println("\tCRD " + removal.name + " not found, skipping deletion.")
+			continue
+		}
+		if len(removal.foreign(helper.Namespace())) > 0 && !flags.force {
+			fmt.Println("\tCRD " + removal.name + " kept, it has instances outside namespace " + helper.Namespace() + " (use --force to delete it).")
+			continue
+		}
+		if err = helper.DeleteCRD(ctx, removal.name); err != nil {
+			fmt.Println("\tCRD " + removal.name + " could not be deleted: " + err.Error())
+			continue
+		}
+		if err = helper.WaitForCRDDeletion(ctx, removal.name); err != nil {
+			fmt.Println("\tCRD " + removal.name + " is stuck deleting.")
+			reportStuckCRD(ctx, helper, removal.name)
+			continue
+		}
+		fmt.Println("\tCRD " + removal.name + " deleted successfully.")
+	}
+	reportStuckNamespace(ctx, helper, helper.Namespace())
+
+	fmt.Println(" 🙁 Sad to see you go.")
+	return nil
+}
+
+func mdaiUninstallPreview(ctx context.Context, flags uninstallFlags) error {
 	helper, err := kubehelper.New(kubehelper.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to initialize kubehelper: %w", err)
 	}
 
-	for _, crd := range customResourceDefinitions() {
-		if err = helper.DeleteCRD(ctx, crd); err != nil {
-			fmt.Println("\tCRD " + crd + " not found, skipping deletion.")
-			continue
+	helmclient := mdaihelm.NewClient(mdaihelm.WithContext(ctx))
+	resources, err := helmclient.ReleaseResources("mdai-cluster")
+	switch {
+	case errors.Is(err, driver.ErrReleaseNotFound):
+		fmt.Println("helm release " + PurpleStyle.Render("mdai-cluster") + " not found, skipping.")
+	case err != nil:
+		return fmt.Errorf("failed to get release resources: %w", err)
+	default:
+		fmt.Printf("helm release %s will be uninstalled, removing %d resource(s):\n", PurpleStyle.Render("mdai-cluster"), len(resources))
+		for _, resource := range resources {
+			fmt.Println("\t" + resource.String())
 		}
-		fmt.Println("\tCRD " + crd + " deleted successfully.")
 	}
-	fmt.Println(" 🙁 Sad to see you go.")
+
+	if flags.keepCRDs {
+		fmt.Println("CRDs will be kept.")
+	} else {
+		removals, err := crdRemovals(ctx, helper)
+		if err != nil {
+			return err
+		}
+		for _, removal := range removals {
+			switch foreign := removal.foreign(helper.Namespace()); {
+			case !removal.found:
+				fmt.Println("CRD " + PurpleStyle.Render(removal.name) + " not found, skipping.")
+			case len(foreign) > 0 && !flags.force:
+				printForeignInstances(removal, helper.Namespace())
+			default:
+				fmt.Printf("CRD %s will be deleted, removing %d instance(s):\n", PurpleStyle.Render(removal.name), len(removal.instances))
+				for _, instance := range removal.instances {
+					fmt.Println("\t" + instanceString(instance))
+				}
+			}
+		}
+	}
+
+	reportStuckNamespace(ctx, helper, helper.Namespace())
 	return nil
 }
+
+type crdRemoval struct {
+	name      string
+	found     bool
+	instances []unstructured.Unstructured
+}
+
+func (r crdRemoval) foreign(namespace string) []unstructured.Unstructured {
+	var foreign []unstructured.Unstructured
+	for _, instance := range r.instances {
+		if instance.GetNamespace() != namespace {
+			foreign = append(foreign, instance)
+		}
+	}
+	return foreign
+}
+
+func crdRemovals(ctx context.Context, helper *kubehelper.Helper) ([]crdRemoval, error) {
+	removals := make([]crdRemoval, 0, len(customResourceDefinitions()))
+	for _, name := range customResourceDefinitions() {
+		removal := crdRemoval{name: name}
+		crd, err := helper.GetCRD(ctx, name)
+		switch {
+		case k8serrors.IsNotFound(err):
+		case err != nil:
+			return nil, fmt.Errorf("failed to get crd %s: %w", name, err)
+		default:
+			removal.found = true
+			if removal.instances, err = helper.ListCustomResources(ctx, crd); err != nil {
+				return nil, err
+			}
+		}
+		removals = append(removals, removal)
+	}
+	return removals, nil
+}
+
+func instanceString(obj unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetKind() + " " + obj.GetName()
+	}
+	return obj.GetKind() + " " + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+func printForeignInstances(removal crdRemoval, namespace string) {
+	foreign := removal.foreign(namespace)
+	if len(foreign) == 0 {
+		return
+	}
+	fmt.Printf("CRD %s will not be deleted, it has %d instance(s) outside namespace %s:\n", PurpleStyle.Render(removal.name), len(foreign), namespace)
+	for _, instance := range foreign {
+		fmt.Println("\t" + instanceString(instance))
+	}
+}
+
+func reportStuckCRD(ctx context.Context, helper *kubehelper.Helper, name string) {
+	crd, err := helper.GetCRD(ctx, name)
+	if err != nil {
+		return
+	}
+	if len(crd.Finalizers) > 0 {
+		fmt.Printf("\t\tfinalizers: %s\n", strings.Join(crd.Finalizers, ", "))
+	}
+	instances, err := helper.ListCustomResources(ctx, crd)
+	if err != nil {
+		return
+	}
+	for _, instance := range instances {
+		if len(instance.GetFinalizers()) == 0 {
+			continue
+		}
+		fmt.Printf("\t\t%s is waiting on finalizers: %s\n", instanceString(instance), strings.Join(instance.GetFinalizers(), ", "))
+	}
+}
+
+func reportStuckNamespace(ctx context.Context, helper *kubehelper.Helper, name string) {
+	ns, err := helper.GetNamespace(ctx, name)
+	if err != nil || ns.Status.Phase != corev1.NamespaceTerminating {
+		return
+	}
+	since := ""
+	if ns.DeletionTimestamp != nil {
+		since = " since " + ns.DeletionTimestamp.Format(time.RFC3339)
+	}
+	fmt.Printf("namespace %s is stuck terminating%s:\n", PurpleStyle.Render(name), since)
+	for _, condition := range ns.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		fmt.Printf("\t%s: %s\n", condition.Type, condition.Message)
+	}
+	if len(ns.Spec.Finalizers) > 0 {
+		finalizers := make([]string, 0, len(ns.Spec.Finalizers))
+		for _, finalizer := range ns.Spec.Finalizers {
+			finalizers = append(finalizers, string(finalizer))
+		}
+		fmt.Printf("\tfinalizers: %s\n", strings.Join(finalizers, ", "))
+	}
+}
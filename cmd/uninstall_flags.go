@@ -1,7 +1,15 @@
 package cmd
 
+import "time"
+
 type uninstallFlags struct {
-	confirm bool
-	debug   bool
-	quiet   bool
+	confirm  bool
+	debug    bool
+	quiet    bool
+	dryRun   bool
+	keepCRDs bool
+	force    bool
+	backup   string
+	timeout  time.Duration
+	retries  int
 }
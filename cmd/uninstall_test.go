@@ -3,6 +3,11 @@ package cmd
 import (
 	"errors"
 	"testing"
+
+	"github.com/decisiveai/mdai-cli/internal/operator"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestUninstallCommandErr(t *testing.T) {
@@ -17,7 +22,79 @@ func TestUninstallCommandErr(t *testing.T) {
 			args: []string{"uninstall", "--debug", "--quiet"},
 			err:  errors.New(`if any flags in the group [debug quiet] are set none of the others can be; [debug quiet] were all set`),
 		},
+		{
+			name: "uninstall with non-positive --timeout",
+			args: []string{"uninstall", "--timeout", "0s"},
+			err:  errors.New("invalid timeout: 0s"),
+		},
+		{
+			name: "uninstall with non-positive --retries",
+			args: []string{"uninstall", "--retries", "0"},
+			err:  errors.New("invalid retries: 0"),
+		},
+		{
+			name: "uninstall with both --keep-crds and --force",
+			args: []string{"uninstall", "--keep-crds", "--force"},
+			err:  errors.New(`if any flags in the group [keep-crds force] are set none of the others can be; [force keep-crds] were all set`),
+		},
+		{
+			name: "uninstall with both --dry-run and --confirm",
+			args: []string{"uninstall", "--dry-run", "--confirm"},
+			err:  errors.New(`if any flags in the group [dry-run confirm] are set none of the others can be; [confirm dry-run] were all set`),
+		},
 	}
 
 	errTests.Run(t)
 }
+
+func TestEngineTemplatePartOf(t *testing.T) {
+	manifest, err := embedFS.ReadFile("templates/mdai-operator.yaml")
+	require.NoError(t, err)
+	var engine unstructured.Unstructured
+	require.NoError(t, yaml.Unmarshal(manifest, &engine.Object))
+
+	require.Equal(t, operator.PartOf, engine.GetLabels()[operator.PartOfLabel])
+}
+
+func TestCRDRemovalForeign(t *testing.T) {
+	instance := func(kind, namespace, name string) unstructured.Unstructured {
+		obj := unstructured.Unstructured{}
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetLabels(map[string]string{operator.PartOfLabel: operator.PartOf})
+		return obj
+	}
+	engines := crdRemoval{name: operator.EngineCRD, found: true, instances: []unstructured.Unstructured{
+		instance("MyDecisiveEngine", "mdai", "engine"),
+		instance("MyDecisiveEngine", "mdai-staging", "engine"),
+	}}
+	collectors := crdRemoval{name: "opentelemetrycollectors.opentelemetry.io", found: true, instances: []unstructured.Unstructured{
+		instance("OpenTelemetryCollector", "mdai", "gateway"),
+		instance("OpenTelemetryCollector", "mdai-staging", "gateway"),
+	}}
+	instrumentations := crdRemoval{name: "instrumentations.opentelemetry.io", found: true, instances: []unstructured.Unstructured{
+		instance("Instrumentation", "mdai", "java"),
+	}}
+
+	tests := []struct {
+		name      string
+		removal   crdRemoval
+		namespace string
+		want      []string
+	}{
+		{name: "engines in another namespace", removal: engines, namespace: "mdai", want: []string{"MyDecisiveEngine mdai-staging/engine"}},
+		{name: "collectors in another namespace", removal: collectors, namespace: "mdai-staging", want: []string{"OpenTelemetryCollector mdai/gateway"}},
+		{name: "only the uninstalled namespace", removal: instrumentations, namespace: "mdai"},
+		{name: "not found", removal: crdRemoval{name: "opampbridges.opentelemetry.io"}, namespace: "mdai"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, obj := range tt.removal.foreign(tt.namespace) {
+				got = append(got, instanceString(obj))
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
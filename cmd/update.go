@@ -1,29 +1,33 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"slices"
 	"strings"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/decisiveai/mdai-cli/internal/editor"
 	"github.com/decisiveai/mdai-cli/internal/operator"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 func NewUpdateCommand() *cobra.Command {
 	flags := updateFlags{}
 	cmd := &cobra.Command{
 		GroupID: "configuration",
-		Use:     "update [-f FILE] [--config CONFIG-TYPE] [--phase PHASE] [--block BLOCK]",
+		Use:     "update [-f FILE... [--var KEY=VALUE]... [--vars-file FILE]] [--config CONFIG-TYPE] [--phase PHASE] [--block BLOCK]",
 		Short:   "update a configuration",
 		Long:    "update a configuration file or edit a configuration in an editor",
 		Example: `	mdai update -f /path/to/mdai-operator.yaml  # update mdai-operator configuration from file
 	mdai update --config=otel                   # edit otel collector configuration in $EDITOR
+	mdai update --config=mdai                   # edit the full mdai engine spec in $EDITOR
 	mdai update --config=otel --phase=logs      # jump to logs block
-	mdai update --config=otel --block=receivers # jump to receivers block`,
+	mdai update --config=otel --block=receivers # jump to receivers block
+	mdai update -f otel.tmpl --vars-file prod.yaml --var env=prod # render an otel config template and apply it
+	mdai update -f base.yaml -f security.yaml -f exporters.yaml   # merge otel config fragments and apply them`,
 		PreRunE: func(_ *cobra.Command, _ []string) error {
 			switch {
 			case flags.config != "" && !slices.Contains(supportedUpdateConfigTypes(), flags.config):
@@ -34,6 +38,9 @@ func NewUpdateCommand() *cobra.Command {
 
 			case flags.block != "" && !slices.Contains(supportedBlocks(), flags.block):
 				return fmt.Errorf("invalid block: %s", flags.block)
+
+			case flags.config == "mdai" && (flags.block != "" || flags.phase != ""):
+				return errors.New("--block and --phase only apply to --config otel")
 			}
 
 			return nil
@@ -42,82 +49,167 @@ func NewUpdateCommand() *cobra.Command {
 			ctx := cmd.Context()
 
 			switch {
-			case flags.config != "":
-				var otelConfig string
-
-				get, err := operator.GetOperator(ctx)
+			case flags.config == "mdai":
+				manifest, err := operator.GetEngineManifest(ctx)
 				if err != nil {
 					return err
 				}
-				otelConfig = get.Spec.TelemetryModule.Collectors[0].Spec.Config
-				f, err := os.CreateTemp("", "otelconfig")
-				if err != nil {
-					return fmt.Errorf("error creating %s config temp file: %w", flags.config, err)
-				}
-				if _, err := f.WriteString(otelConfig); err != nil {
-					return fmt.Errorf("error saving %s config temp file: %w", flags.config, err)
+				engine := make(map[string]any)
+				if err := yaml.Unmarshal(manifest, &engine); err != nil {
+					return fmt.Errorf("failed to parse engine: %w", err)
 				}
-				if err := f.Close(); err != nil {
-					return fmt.Errorf("error closing %s config temp file: %w", flags.config, err)
-				}
-
-				defer func() {
-					_ = os.Remove(f.Name())
-				}()
+				metadata, _ := engine["metadata"].(map[string]any)
+				name, _ := metadata["name"].(string)
 
-				m := editor.NewModel(f.Name(), flags.block, flags.phase)
-				if _, err := tea.NewProgram(m).Run(); err != nil {
+				edited, err := editConfigTemp("mdai-engine-*.yaml", flags.config, manifest, "", "", func(config string) error {
+					return operator.ValidateEngine(ctx, []byte(config), name)
+				})
+				if err != nil || edited == nil {
 					return err
 				}
+				if err := operator.ApplyEngine(ctx, edited); err != nil {
+					return fmt.Errorf("error updating mdai engine: %w", err)
+				}
+				fmt.Println(flags.config + " configuration updated")
 
-				var applyConfig bool
-				form := huh.NewForm(
-					huh.NewGroup(
-						huh.NewConfirm().
-							Title("apply config?").
-							Value(&applyConfig).
-							Affirmative("yes!").
-							Negative("no."),
-					),
-				)
-				if err := form.Run(); err != nil {
+			case flags.config == "otel":
+				collector, err := operator.GetCollector(ctx)
+				if err != nil {
 					return err
 				}
-				if !applyConfig {
-					fmt.Println(flags.config + " configuration not updated")
+				edited, err := editConfigTemp("otelconfig-*.yaml", flags.config, []byte(collector.Spec.Config), flags.block, flags.phase, func(config string) error {
+					if err := validateOtelConfig(config); err != nil {
+						return err
+					}
+					warnOtelLint(config)
 					return nil
+				})
+				if err != nil || edited == nil {
+					return err
 				}
-
-				otelConfigBytes, _ := os.ReadFile(f.Name())
-				if err := operator.UpdateOTELConfig(ctx, string(otelConfigBytes)); err != nil {
+				if err := operator.UpdateOTELConfig(ctx, string(edited)); err != nil {
 					return fmt.Errorf("error updating otel collector configuration: %w", err)
 				}
 				fmt.Println(flags.config + " configuration updated")
 
-			case flags.file != "":
-				otelConfigBytes, err := os.ReadFile(flags.file)
+			case len(flags.files) > 0:
+				otelConfig, err := readOtelConfig(flags.files, flags.varsFile, flags.vars)
 				if err != nil {
-					return fmt.Errorf(`error reading file "%s": %w`, flags.file, err)
+					return err
 				}
-				if err := operator.UpdateOTELConfig(ctx, string(otelConfigBytes)); err != nil {
+				if err := validateOtelConfig(otelConfig); err != nil {
+					return fmt.Errorf("invalid otel config, not applied:\n%w", err)
+				}
+				warnOtelLint(otelConfig)
+				if err := operator.UpdateOTELConfig(ctx, otelConfig); err != nil {
 					return fmt.Errorf("error updating otel collector configuration: %w", err)
 				}
-				fmt.Println(flags.config + " configuration updated")
+				fmt.Println("otel configuration updated")
 			}
 
 			return nil
 		},
 	}
-	cmd.Flags().StringVarP(&flags.file, "file", "f", "", "file to update")
-	cmd.Flags().StringVarP(&flags.config, "config", "c", "", "config type to update ["+strings.Join(supportedUpdateConfigTypes(), ", ")+"]")
+	cmd.Flags().StringArrayVarP(&flags.files, "file", "f", nil, "file to update, repeat to deep-merge config fragments in order")
+	cmd.Flags().StringArrayVar(&flags.vars, "var", nil, "template variable for -f, KEY=VALUE with dotted keys for nested values")
+	cmd.Flags().StringVar(&flags.varsFile, "vars-file", "", "yaml file with template variables for -f")
+	cmd.Flags().StringVarP(&flags.config, "config", "c", "", "config type to edit in $VISUAL or $EDITOR ["+strings.Join(supportedUpdateConfigTypes(), ", ")+"]")
 	cmd.Flags().StringVar(&flags.block, "block", "", "block to jump to ["+strings.Join(supportedBlocks(), ", ")+"]")
 	cmd.Flags().StringVar(&flags.phase, "phase", "", "phase to jump to ["+strings.Join(supportedPhases(), ", ")+"]")
 
 	cmd.MarkFlagsMutuallyExclusive("file", "config")
 	cmd.MarkFlagsOneRequired("file", "config")
+	cmd.MarkFlagsMutuallyExclusive("var", "config")
+	cmd.MarkFlagsMutuallyExclusive("vars-file", "config")
 
 	cmd.DisableFlagsInUseLine = true
 	cmd.SilenceUsage = true
 
 	return cmd
 }
+
+// editConfigTemp keeps the temp file when the user gives up on an invalid config.
+func editConfigTemp(pattern, configType string, config []byte, block, phase string, validate func(string) error) ([]byte, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s config temp file: %w", configType, err)
+	}
+	if _, err := f.Write(config); err != nil {
+		return nil, fmt.Errorf("error saving %s config temp file: %w", configType, err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("error closing %s config temp file: %w", configType, err)
+	}
+
+	keep := false
+	defer func() {
+		if !keep {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if err := editConfig(f.Name(), configType, block, phase, validate); err != nil {
+		keep = true
+		fmt.Printf("your changes are kept in %s\n", PurpleStyle.Render(f.Name()))
+		return nil, err
+	}
+
+	var applyConfig bool
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("apply config?").
+				Value(&applyConfig).
+				Affirmative("yes!").
+				Negative("no."),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+	if !applyConfig {
+		fmt.Println(configType + " configuration not updated")
+		return nil, nil
+	}
+
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return nil, fmt.Errorf("error reading edited config: %w", err)
+	}
+	return edited, nil
+}
+
+// editConfig reopens the editor until the config is valid or the user gives up.
+func editConfig(filename, configType, block, phase string, validate func(string) error) error {
+	ed, err := editor.FromEnv()
+	if err != nil {
+		return err
+	}
+	for {
+		if err := editor.Run(ed, filename, block, phase); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("error reading edited config: %w", err)
+		}
+		invalid := validate(string(edited))
+		if invalid == nil {
+			return nil
+		}
+		fmt.Printf("%s\n%v\n", DisabledStyle.Render("invalid "+configType+" config:"), invalid)
+
+		editAgain := true
+		if err := huh.NewConfirm().
+			Title("edit again?").
+			Value(&editAgain).
+			Affirmative("yes!").
+			Negative("no.").
+			Run(); err != nil {
+			return err
+		}
+		if !editAgain {
+			return errors.New(configType + " configuration not updated")
+		}
+	}
+}
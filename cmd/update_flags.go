@@ -1,8 +1,10 @@
 package cmd
 
 type updateFlags struct {
-	file   string
-	config string
-	phase  string
-	block  string
+	files    []string
+	vars     []string
+	varsFile string
+	config   string
+	phase    string
+	block    string
 }
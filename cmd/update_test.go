@@ -27,6 +27,16 @@ func TestUpdateCommandErr(t *testing.T) {
 			args: []string{"update", "--config", "otel", "--phase", "foo"},
 			err:  errors.New("invalid phase: foo"),
 		},
+		{
+			name: "update command with mdai config and block flag",
+			args: []string{"update", "--config", "mdai", "--block", "receivers"},
+			err:  errors.New("--block and --phase only apply to --config otel"),
+		},
+		{
+			name: "update command with config and var flags",
+			args: []string{"update", "--config", "otel", "--var", "env=prod"},
+			err:  errors.New("if any flags in the group [var config] are set none of the others can be; [config var] were all set"),
+		},
 	}
 
 	errTests.Run(t)
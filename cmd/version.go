@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/decisiveai/mdai-cli/internal/compat"
+	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
+	"github.com/decisiveai/mdai-cli/internal/operator"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func NewVersionCommand() *cobra.Command {
+	flags := versionFlags{}
+	cmd := &cobra.Command{
+		GroupID: "installation",
+		Use:     "version [--check]",
+		Short:   "show the mdai version",
+		Long:    "show the mdai version and, with --check, whether it is compatible with the installed chart and MyDecisiveEngine API",
+		Example: `  mdai version         # show the mdai version
+  mdai version --check # check compatibility with the installed cluster`,
+		Args: cobra.NoArgs,
+		Annotations: map[string]string{
+			optionalKubeconfig: "",
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			fmt.Printf("mdai version: %s (git sha: %s), built: %s\n", Version, GitSha, BuildTime)
+			if !flags.check {
+				return nil
+			}
+			return mdaiVersionCheck(cmd.Context())
+		},
+	}
+	cmd.Flags().BoolVar(&flags.check, "check", false, "check compatibility with the installed chart and MyDecisiveEngine API")
+
+	cmd.DisableFlagsInUseLine = true
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func mdaiVersionCheck(ctx context.Context) error {
+	entry, known, err := compat.ForCLI(Version)
+	if err != nil {
+		return err
+	}
+	if !known {
+		fmt.Printf("%s development build, checking against the latest compatibility matrix entry (%s)\n",
+			lipgloss.NewStyle().Foreground(red).Render("!"), entry.CLI)
+	}
+
+	var warnings []string
+	t := styledTable().Headers(versionCheckHeaders()...)
+
+	helmclient := mdaihelm.NewClient(mdaihelm.WithContext(ctx))
+	rel, err := helmclient.Release("mdai-cluster")
+	switch {
+	case errors.Is(err, driver.ErrReleaseNotFound):
+		t.Row("mdai-cluster chart", NoDataString, strings.Join(entry.Charts["mdai-cluster"], ", "), NoDataString)
+	case err != nil:
+		return fmt.Errorf("failed to get mdai-cluster release: %w", err)
+	default:
+		version := rel.Chart.Metadata.Version
+		ok := entry.SupportsChart("mdai-cluster", version)
+		t.Row("mdai-cluster chart", version, strings.Join(entry.Charts["mdai-cluster"], ", "), checkMark(ok))
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("mdai-cluster %s is not supported by mdai %s: install, outdated and rollback-release may misbehave", version, Version))
+		}
+	}
+
+	helper, err := kubehelper.New(kubehelper.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	crd, err := helper.GetCRD(ctx, operator.EngineCRD)
+	if err != nil {
+		t.Row("MyDecisiveEngine API", NoDataString, strings.Join(entry.EngineAPIVersions, ", "), NoDataString)
+	} else {
+		var served []string
+		for _, version := range crd.Spec.Versions {
+			if version.Served {
+				served = append(served, version.Name)
+			}
+		}
+		ok := entry.SupportsEngineAPI(served)
+		t.Row("MyDecisiveEngine API", strings.Join(served, ", "), strings.Join(entry.EngineAPIVersions, ", "), checkMark(ok))
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("MyDecisiveEngine API %s is not supported by mdai %s: filter, enable, disable, get, update, backup and restore may misbehave", strings.Join(served, ", "), Version))
+		}
+	}
+
+	fmt.Println(t)
+	if len(warnings) == 0 {
+		return nil
+	}
+	for _, warning := range warnings {
+		fmt.Println(lipgloss.NewStyle().Foreground(red).Render(DisabledString) + " " + warning)
+	}
+	fmt.Println("run " + PurpleStyle.Render("mdai self-update") + " or " + PurpleStyle.Render("mdai install") + " to bring them in line")
+	return errors.New("incompatible versions found")
+}
+
+func checkMark(ok bool) string {
+	if ok {
+		return EnabledString
+	}
+	return DisabledString
+}
@@ -0,0 +1,5 @@
+package cmd
+
+type versionFlags struct {
+	check bool
+}
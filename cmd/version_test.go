@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVersionCommandErr(t *testing.T) {
+	errTests := testCmdErrs{
+		{
+			name: "version command with args",
+			args: []string{"version", "latest"},
+			err:  errors.New(`unknown command "latest" for "mdai version"`),
+		},
+	}
+
+	errTests.Run(t)
+}
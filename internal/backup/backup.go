@@ -0,0 +1,103 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const FormatVersion = 1
+
+type Backup struct {
+	FormatVersion int            `yaml:"formatVersion"`
+	CreatedAt     time.Time      `yaml:"createdAt"`
+	CLIVersion    string         `yaml:"cliVersion"`
+	Chart         Chart          `yaml:"chart"`
+	Engine        map[string]any `yaml:"engine"`
+}
+
+type Chart struct {
+	Name    string         `yaml:"name"`
+	Version string         `yaml:"version"`
+	Values  map[string]any `yaml:"values"`
+}
+
+func Load(filename string) (*Backup, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to read backup "%s": %w`, filename, err)
+	}
+	b := new(Backup)
+	if err := yaml.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf(`failed to parse backup "%s": %w`, filename, err)
+	}
+	if b.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf(`backup "%s" has unsupported format version %d`, filename, b.FormatVersion)
+	}
+	if len(b.Engine) == 0 {
+		return nil, fmt.Errorf(`backup "%s" has no engine`, filename)
+	}
+	return b, nil
+}
+
+func (b *Backup) Save(filename string) error {
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("failed to marshal backup: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o600); err != nil { //nolint: mnd
+		return fmt.Errorf(`failed to write backup "%s": %w`, filename, err)
+	}
+	return nil
+}
+
+func (b *Backup) EngineManifest() ([]byte, error) {
+	return yaml.Marshal(b.Engine)
+}
+
+func (b *Backup) EngineName() string {
+	return (&unstructured.Unstructured{Object: b.Engine}).GetName()
+}
+
+func (b *Backup) EngineAPIVersion() string {
+	return (&unstructured.Unstructured{Object: b.Engine}).GetAPIVersion()
+}
+
+func (b *Backup) Filters() int {
+	spec, _ := b.Engine["spec"].(map[string]any)
+	telemetryModule, _ := spec["telemetryModule"].(map[string]any)
+	collectors, _ := telemetryModule["collectors"].([]any)
+	var count int
+	for _, collector := range collectors {
+		c, _ := collector.(map[string]any)
+		telemetryFiltering, _ := c["telemetryFiltering"].(map[string]any)
+		filters, _ := telemetryFiltering["filters"].([]any)
+		count += len(filters)
+	}
+	return count
+}
+
+func (b *Backup) Compatible(chartVersion string, engineVersions []string) error {
+	backupVersion, installedVersion := canonical(b.Chart.Version), canonical(chartVersion)
+	if semver.MajorMinor(backupVersion) != semver.MajorMinor(installedVersion) {
+		return fmt.Errorf("backup was taken from chart %s %s, installed chart is %s", b.Chart.Name, b.Chart.Version, chartVersion)
+	}
+	_, version, _ := strings.Cut(b.EngineAPIVersion(), "/")
+	if !slices.Contains(engineVersions, version) {
+		return fmt.Errorf("backup engine version %s is not served by the cluster [%s]", version, strings.Join(engineVersions, ", "))
+	}
+	return nil
+}
+
+func canonical(version string) string {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return semver.Canonical(version)
+}
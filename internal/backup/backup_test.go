@@ -0,0 +1,29 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFilters(t *testing.T) {
+	var engine map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(`
+spec:
+  replicas: 2
+  ports:
+    - port: 4317
+  telemetryModule:
+    collectors:
+      - name: gateway
+        telemetryFiltering:
+          filters:
+            - name: a
+            - name: b
+      - name: other
+`), &engine))
+
+	require.Equal(t, 2, (&Backup{Engine: engine}).Filters())
+	require.Equal(t, 0, (&Backup{}).Filters())
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/decisiveai/mdai-cli/internal/config"
+)
+
+const sourceFile = "source"
+
+var ErrNotCached = errors.New("chart not cached")
+
+type Entry struct {
+	Name     string
+	Version  string
+	Digest   string
+	Source   string
+	Path     string
+	Size     int64
+	CachedAt time.Time
+}
+
+// Dir holds charts as <name>/<version>/<sha256>/<file>.tgz, keeping the file
+// name so a .prov next to it still verifies, beside the source they came from.
+func Dir() string {
+	return filepath.Join(filepath.Dir(config.Path()), "cache")
+}
+
+func Digest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open chart: %w", err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read chart: %w", err)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func List() ([]Entry, error) {
+	matches, err := filepath.Glob(filepath.Join(Dir(), "*", "*", "*", "*.tgz"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chart cache: %w", err)
+	}
+	entries := make([]Entry, 0, len(matches))
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			continue
+		}
+		rel, _ := filepath.Rel(Dir(), match)
+		parts := strings.Split(rel, string(filepath.Separator))
+		source, _ := os.ReadFile(filepath.Join(filepath.Dir(match), sourceFile))
+		entries = append(entries, Entry{
+			Name:     parts[0],
+			Version:  parts[1],
+			Digest:   "sha256:" + parts[2],
+			Source:   string(source),
+			Path:     match,
+			Size:     info.Size(),
+			CachedAt: info.ModTime(),
+		})
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Version, b.Version); c != 0 {
+			return c
+		}
+		return b.CachedAt.Compare(a.CachedAt)
+	})
+	return entries, nil
+}
+
+// Lookup returns the cached chart for name and version. Without a pinned
+// digest only a copy from the same source matches, the most recent first.
+func Lookup(name, version, digest, source string) (*Entry, error) {
+	entries, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Name != name || entry.Version != version {
+			continue
+		}
+		if (digest != "" && entry.Digest == digest) || (digest == "" && entry.Source == source) {
+			return &entry, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s-%s", ErrNotCached, name, version)
+}
+
+func Store(name, version, source, chartPath string) (*Entry, error) {
+	digest, err := Digest(chartPath)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(Dir(), name, version, strings.TrimPrefix(digest, "sha256:"))
+	if err := os.MkdirAll(dir, 0o700); err != nil { //nolint: mnd
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	dest := filepath.Join(dir, filepath.Base(chartPath))
+	if err := copyFile(chartPath, dest); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(chartPath + ".prov"); err == nil {
+		if err := copyFile(chartPath+".prov", dest+".prov"); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, sourceFile), []byte(source), 0o600); err != nil { //nolint: mnd
+		return nil, fmt.Errorf("failed to write cache: %w", err)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached chart: %w", err)
+	}
+	return &Entry{Name: name, Version: version, Digest: digest, Source: source, Path: dest, Size: info.Size(), CachedAt: info.ModTime()}, nil
+}
+
+func (e Entry) Remove() error {
+	if err := os.RemoveAll(filepath.Dir(e.Path)); err != nil {
+		return fmt.Errorf("failed to remove cached chart %s-%s: %w", e.Name, e.Version, err)
+	}
+	_ = os.Remove(filepath.Join(Dir(), e.Name, e.Version))
+	_ = os.Remove(filepath.Join(Dir(), e.Name))
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	chartPath := filepath.Join(t.TempDir(), "mdai-cluster-0.0.2.tgz")
+	require.NoError(t, os.WriteFile(chartPath, []byte("chart"), 0o600))
+	stored, err := Store("mdai-cluster", "0.0.2", "oci://registry.example.com/charts", chartPath)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		digest string
+		source string
+		found  bool
+	}{
+		{name: "same source", source: "oci://registry.example.com/charts", found: true},
+		{name: "other source", source: "https://charts.example.com"},
+		{name: "pinned digest from other source", digest: stored.Digest, source: "https://charts.example.com", found: true},
+		{name: "other digest", digest: "sha256:00", source: "oci://registry.example.com/charts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := Lookup("mdai-cluster", "0.0.2", tt.digest, tt.source)
+			if !tt.found {
+				require.ErrorIs(t, err, ErrNotCached)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, stored.Path, entry.Path)
+		})
+	}
+}
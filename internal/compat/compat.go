@@ -0,0 +1,66 @@
+package compat
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed matrix.yaml
+var matrixYAML []byte
+
+type Entry struct {
+	CLI               string              `yaml:"cli"`
+	Charts            map[string][]string `yaml:"charts"`
+	EngineAPIVersions []string            `yaml:"engineAPIVersions"`
+}
+
+func Matrix() ([]Entry, error) {
+	var entries []Entry
+	if err := yaml.Unmarshal(matrixYAML, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse compatibility matrix: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("compatibility matrix is empty")
+	}
+	return entries, nil
+}
+
+// ForCLI returns the matrix entry for the major.minor of version. Versions
+// that are not semver, such as development builds, get the latest entry.
+func ForCLI(version string) (*Entry, bool, error) {
+	entries, err := Matrix()
+	if err != nil {
+		return nil, false, err
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) {
+		return &entries[len(entries)-1], false, nil
+	}
+	for _, entry := range entries {
+		if semver.MajorMinor("v"+entry.CLI) == semver.MajorMinor(version) {
+			return &entry, true, nil
+		}
+	}
+	return nil, false, fmt.Errorf("CLI version %s is not in the compatibility matrix", version)
+}
+
+func (e Entry) SupportsChart(name, version string) bool {
+	return slices.Contains(e.Charts[name], strings.TrimPrefix(version, "v"))
+}
+
+func (e Entry) SupportsEngineAPI(versions []string) bool {
+	for _, version := range versions {
+		if slices.Contains(e.EngineAPIVersions, version) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package compat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForCLI(t *testing.T) {
+	entries, err := Matrix()
+	require.NoError(t, err)
+	latest := entries[len(entries)-1]
+
+	tests := []struct {
+		name    string
+		version string
+		want    string
+		tested  bool
+		err     string
+	}{
+		{name: "release", version: "0.1.0", want: "0.1", tested: true},
+		{name: "v prefix", version: "v0.1.3", want: "0.1", tested: true},
+		{name: "prerelease", version: "0.1.0-rc1", want: "0.1", tested: true},
+		{name: "development build", version: "dev", want: latest.CLI},
+		{name: "unknown minor", version: "9.9.0", err: "CLI version v9.9.0 is not in the compatibility matrix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, tested, err := ForCLI(tt.version)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, entry.CLI)
+			require.Equal(t, tt.tested, tested)
+		})
+	}
+}
+
+func TestSupportsChart(t *testing.T) {
+	entry := Entry{Charts: map[string][]string{"mdai-cluster": {"0.0.2", "0.0.3"}}}
+	tests := []struct {
+		name    string
+		chart   string
+		version string
+		want    bool
+	}{
+		{name: "supported", chart: "mdai-cluster", version: "0.0.3", want: true},
+		{name: "v prefix", chart: "mdai-cluster", version: "v0.0.2", want: true},
+		{name: "other version", chart: "mdai-cluster", version: "0.0.4"},
+		{name: "other chart", chart: "opentelemetry-demo", version: "0.0.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, entry.SupportsChart(tt.chart, tt.version))
+		})
+	}
+}
+
+func TestSupportsEngineAPI(t *testing.T) {
+	entry := Entry{EngineAPIVersions: []string{"v1"}}
+	tests := []struct {
+		name     string
+		versions []string
+		want     bool
+	}{
+		{name: "served", versions: []string{"v1"}, want: true},
+		{name: "one of several served", versions: []string{"v1alpha1", "v1"}, want: true},
+		{name: "not served", versions: []string{"v1alpha1", "v2"}},
+		{name: "no versions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, entry.SupportsEngineAPI(tt.versions))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/util/homedir"
+)
+
+const (
+	DefaultNamespace = "mdai"
+	DefaultProfile   = "default"
+)
+
+var ErrUnknownKey = errors.New("unknown config key")
+
+type Config struct {
+	CurrentProfile string              `yaml:"current-profile,omitempty"`
+	Profiles       map[string]*Profile `yaml:"profiles,omitempty"`
+	// Namespace predates profiles and is moved into the current one on load.
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type Profile struct {
+	Kubeconfig  string `yaml:"kubeconfig,omitempty"`
+	Kubecontext string `yaml:"kubecontext,omitempty"`
+	Namespace   string `yaml:"namespace,omitempty"`
+	Engine      string `yaml:"engine,omitempty"`
+	Collector   string `yaml:"collector,omitempty"`
+	Output      string `yaml:"output,omitempty"`
+	ChartRepo   string `yaml:"chart-repo,omitempty"`
+}
+
+func Path() string {
+	return filepath.Join(homedir.HomeDir(), ".mdai", "config.yaml")
+}
+
+func Load() (*Config, error) {
+	cfg := new(Config)
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf(`failed to parse config "%s": %w`, Path(), err)
+	}
+	if cfg.Namespace != "" {
+		if profile := cfg.Current(); profile.Namespace == "" {
+			profile.Namespace = cfg.Namespace
+		}
+		cfg.Namespace = ""
+	}
+	return cfg, nil
+}
+
+func (c *Config) Save() error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(Path()), 0o700); err != nil { //nolint: mnd
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(Path(), data, 0o600); err != nil { //nolint: mnd
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+func (c *Config) Current() *Profile {
+	if c.CurrentProfile == "" {
+		c.CurrentProfile = DefaultProfile
+	}
+	return c.Profile(c.CurrentProfile)
+}
+
+func (c *Config) Profile(name string) *Profile {
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*Profile)
+	}
+	if c.Profiles[name] == nil {
+		c.Profiles[name] = new(Profile)
+	}
+	return c.Profiles[name]
+}
+
+func (c *Config) HasProfile(name string) bool {
+	_, ok := c.Profiles[name]
+	return ok
+}
+
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func Keys() []string {
+	return []string{"kubeconfig", "kubecontext", "namespace", "engine", "collector", "output", "chart-repo"}
+}
+
+func (p *Profile) fields() map[string]*string {
+	return map[string]*string{
+		"kubeconfig":  &p.Kubeconfig,
+		"kubecontext": &p.Kubecontext,
+		"namespace":   &p.Namespace,
+		"engine":      &p.Engine,
+		"collector":   &p.Collector,
+		"output":      &p.Output,
+		"chart-repo":  &p.ChartRepo,
+	}
+}
+
+func (p *Profile) Get(key string) (string, error) {
+	field, ok := p.fields()[key]
+	if !ok {
+		return "", fmt.Errorf("%w %q, expected one of %v", ErrUnknownKey, key, Keys())
+	}
+	return *field, nil
+}
+
+// An empty value removes the setting from the profile.
+func (p *Profile) Set(key, value string) error {
+	field, ok := p.fields()[key]
+	if !ok {
+		return fmt.Errorf("%w %q, expected one of %v", ErrUnknownKey, key, Keys())
+	}
+	*field = value
+	return nil
+}
@@ -0,0 +1,397 @@
+package configure
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	ClusterKind = "kind"
+	ClusterEKS  = "eks"
+
+	certificateARNAnnotation = "alb.ingress.kubernetes.io/certificate-arn"
+	ingressClassAnnotation   = "kubernetes.io/ingress.class"
+)
+
+type Answers struct {
+	EngineName         string            `yaml:"engine,omitempty"`
+	KubeContext        string            `yaml:"kubecontext,omitempty"`
+	Namespace          string            `yaml:"namespace,omitempty"`
+	ClusterProfile     string            `yaml:"clusterProfile"`
+	CertificateARN     string            `yaml:"certificateArn,omitempty"`
+	CollectorEndpoints map[string]string `yaml:"collectorEndpoints,omitempty"`
+	IngressClass       string            `yaml:"ingressClass,omitempty"`
+	Exporters          []string          `yaml:"exporters"`
+	OTLPEndpoint       string            `yaml:"otlpEndpoint,omitempty"`
+	Datalyzer          *bool             `yaml:"datalyzer,omitempty"`
+}
+
+type exporter struct {
+	config  map[string]any
+	signals []string
+}
+
+var exporters = map[string]exporter{
+	"debug": {
+		config:  map[string]any{"verbosity": "basic"},
+		signals: []string{"logs", "metrics", "traces"},
+	},
+	"otlp": {
+		signals: []string{"logs", "metrics", "traces"},
+	},
+	"prometheus": {
+		config:  map[string]any{"endpoint": "0.0.0.0:9464"},
+		signals: []string{"metrics"},
+	},
+}
+
+// guardProcessors run first and last in every pipeline of the engine config.
+var guardProcessors = map[string]any{
+	"memory_limiter": map[string]any{"check_interval": "1s", "limit_percentage": int64(80), "spike_limit_percentage": int64(25)},
+	"batch":          map[string]any{},
+}
+
+const insecureReceiverComment = "mdai-lint-disable insecure-receiver"
+
+func ClusterProfiles() []string {
+	return []string{ClusterKind, ClusterEKS}
+}
+
+func Exporters() []string {
+	names := make([]string, 0, len(exporters))
+	for name := range exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GRPCReceivers lists the receivers of the engine template that need an
+// ingress hostname on eks.
+func GRPCReceivers() []string {
+	return []string{"otlp"}
+}
+
+func LoadAnswers(filename string) (*Answers, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to read answers "%s": %w`, filename, err)
+	}
+	answers := new(Answers)
+	if err := yaml.Unmarshal(data, answers); err != nil {
+		return nil, fmt.Errorf(`failed to parse answers "%s": %w`, filename, err)
+	}
+	return answers, nil
+}
+
+func (a *Answers) Save(filename string) error {
+	data, err := yaml.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to marshal answers: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o600); err != nil { //nolint: mnd
+		return fmt.Errorf(`failed to write answers "%s": %w`, filename, err)
+	}
+	return nil
+}
+
+func (a *Answers) Validate() error {
+	var errs []error
+	if !slices.Contains(ClusterProfiles(), a.ClusterProfile) {
+		errs = append(errs, fmt.Errorf("invalid clusterProfile: %q, expected one of %v", a.ClusterProfile, ClusterProfiles()))
+	}
+	if a.ClusterProfile == ClusterEKS {
+		if err := ValidateCertificateARN(a.CertificateARN); err != nil {
+			errs = append(errs, err)
+		}
+		for _, receiver := range GRPCReceivers() {
+			if err := ValidateHostname(a.CollectorEndpoints[receiver]); err != nil {
+				errs = append(errs, fmt.Errorf("collectorEndpoints.%s: %w", receiver, err))
+			}
+		}
+		if a.IngressClass == "" {
+			errs = append(errs, errors.New("ingressClass is required for eks"))
+		}
+	}
+	errs = append(errs, ValidateExporters(a.Exporters))
+	if slices.Contains(a.Exporters, "otlp") && a.OTLPEndpoint == "" {
+		errs = append(errs, errors.New("otlpEndpoint is required for the otlp exporter"))
+	}
+	return errors.Join(errs...)
+}
+
+func ValidateCertificateARN(arn string) error {
+	if !strings.HasPrefix(arn, "arn:aws:acm:") {
+		return fmt.Errorf("invalid certificateArn: %q, expected arn:aws:acm:REGION:ACCOUNT:certificate/ID", arn)
+	}
+	return nil
+}
+
+func ValidateHostname(hostname string) error {
+	if hostname == "" || strings.ContainsAny(hostname, " /:") {
+		return fmt.Errorf("invalid hostname: %q", hostname)
+	}
+	return nil
+}
+
+// ValidateExporters checks that every signal has at least one exporter.
+func ValidateExporters(names []string) error {
+	if len(names) == 0 {
+		return errors.New("at least one exporter is required")
+	}
+	covered := make(map[string]bool)
+	for _, name := range names {
+		e, ok := exporters[name]
+		if !ok {
+			return fmt.Errorf("invalid exporter: %q, expected one of %v", name, Exporters())
+		}
+		for _, signal := range e.signals {
+			covered[signal] = true
+		}
+	}
+	for _, signal := range []string{"logs", "metrics", "traces"} {
+		if !covered[signal] {
+			return fmt.Errorf("no exporter in %v can export %s", names, signal)
+		}
+	}
+	return nil
+}
+
+type Engine struct {
+	Name      string
+	Namespace string
+	Collector string
+	Manifest  []byte
+}
+
+// Engine renders the engine manifest from the template for the answers.
+func (a *Answers) Engine(template []byte) (*Engine, error) {
+	obj, err := decode(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse engine template: %w", err)
+	}
+	engine := unstructured.Unstructured{Object: obj}
+	if a.EngineName != "" {
+		engine.SetName(a.EngineName)
+	}
+	if a.Namespace != "" {
+		engine.SetNamespace(a.Namespace)
+	}
+
+	collectors, _, _ := unstructured.NestedSlice(obj, "spec", "telemetryModule", "collectors")
+	if len(collectors) == 0 {
+		return nil, errors.New("engine template has no collectors")
+	}
+	collector, ok := collectors[0].(map[string]any)
+	if !ok {
+		return nil, errors.New("engine template has an invalid collector")
+	}
+	if a.Datalyzer != nil {
+		collector["measureVolumes"] = *a.Datalyzer
+	}
+
+	switch a.ClusterProfile {
+	case ClusterEKS:
+		if err := a.setIngress(collector); err != nil {
+			return nil, err
+		}
+	default:
+		unstructured.RemoveNestedField(collector, "spec", "ingress")
+	}
+
+	otelConfig, _, _ := unstructured.NestedString(collector, "spec", "config")
+	otelConfig, err = a.otelConfig(otelConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedField(collector, otelConfig, "spec", "config"); err != nil {
+		return nil, fmt.Errorf("failed to set otel config: %w", err)
+	}
+
+	collectors[0] = collector
+	if err := unstructured.SetNestedSlice(obj, collectors, "spec", "telemetryModule", "collectors"); err != nil {
+		return nil, fmt.Errorf("failed to set collectors: %w", err)
+	}
+	collectorName, _, _ := unstructured.NestedString(collector, "name")
+
+	data, err := marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal engine manifest: %w", err)
+	}
+	return &Engine{Name: engine.GetName(), Namespace: engine.GetNamespace(), Collector: collectorName, Manifest: data}, nil
+}
+
+func (a *Answers) setIngress(collector map[string]any) error {
+	annotations, _, _ := unstructured.NestedStringMap(collector, "spec", "ingress", "annotations")
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[certificateARNAnnotation] = a.CertificateARN
+	annotations[ingressClassAnnotation] = a.IngressClass
+	endpoints := make(map[string]any, len(a.CollectorEndpoints))
+	for receiver, hostname := range a.CollectorEndpoints {
+		endpoints[receiver] = hostname
+	}
+	for field, value := range map[string]any{
+		"annotations":        toAnyMap(annotations),
+		"collectorEndpoints": endpoints,
+		"ingressClassName":   a.IngressClass,
+	} {
+		if err := unstructured.SetNestedField(collector, value, "spec", "ingress", field); err != nil {
+			return fmt.Errorf("failed to set ingress %s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// otelConfig points every pipeline at the chosen exporters that support its
+// signal, guards it with memory_limiter and batch and drops duplicates.
+func (a *Answers) otelConfig(config string) (string, error) {
+	cfg, err := decode([]byte(config))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse otel config: %w", err)
+	}
+	chosen := make(map[string]any, len(a.Exporters))
+	for _, name := range a.Exporters {
+		exporterConfig := make(map[string]any)
+		for k, v := range exporters[name].config {
+			exporterConfig[k] = v
+		}
+		if name == "otlp" {
+			exporterConfig["endpoint"] = a.OTLPEndpoint
+		}
+		chosen[name] = exporterConfig
+	}
+	cfg["exporters"] = chosen
+
+	processors, _, _ := unstructured.NestedMap(cfg, "processors")
+	if processors == nil {
+		processors = make(map[string]any, len(guardProcessors))
+	}
+	for name, processorConfig := range guardProcessors {
+		if _, ok := processors[name]; !ok {
+			processors[name] = processorConfig
+		}
+	}
+	cfg["processors"] = processors
+
+	pipelines, _, _ := unstructured.NestedMap(cfg, "service", "pipelines")
+	names := make([]string, 0, len(pipelines))
+	for name := range pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		pipeline, ok := pipelines[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		signal, _, _ := strings.Cut(name, "/")
+		var exporterNames []any
+		for _, exporterName := range a.Exporters {
+			if slices.Contains(exporters[exporterName].signals, signal) {
+				exporterNames = append(exporterNames, exporterName)
+			}
+		}
+		pipeline["exporters"] = exporterNames
+		processorNames, _, _ := unstructured.NestedSlice(pipeline, "processors")
+		pipeline["processors"] = guard(processorNames)
+
+		key := fmt.Sprint(signal, pipeline["receivers"], pipeline["processors"], pipeline["exporters"])
+		if seen[key] {
+			delete(pipelines, name)
+			continue
+		}
+		seen[key] = true
+		pipelines[name] = pipeline
+	}
+	if pipelines != nil {
+		if err := unstructured.SetNestedMap(cfg, pipelines, "service", "pipelines"); err != nil {
+			return "", fmt.Errorf("failed to set pipelines: %w", err)
+		}
+	}
+
+	var node yaml.Node
+	if err := node.Encode(cfg); err != nil {
+		return "", fmt.Errorf("failed to marshal otel config: %w", err)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "receivers" {
+			markInsecureEndpoints(node.Content[i+1])
+		}
+	}
+	data, err := marshal(&node)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal otel config: %w", err)
+	}
+	return string(data), nil
+}
+
+func guard(processors []any) []any {
+	guarded := []any{"memory_limiter"}
+	for _, name := range processors {
+		if name != "memory_limiter" && name != "batch" {
+			guarded = append(guarded, name)
+		}
+	}
+	return append(guarded, "batch")
+}
+
+// markInsecureEndpoints allows the in-cluster 0.0.0.0 receiver endpoints,
+// which the service, ingress and kubectl port-forward need.
+func markInsecureEndpoints(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		for _, child := range node.Content {
+			markInsecureEndpoints(child)
+		}
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value == "endpoint" && strings.HasPrefix(value.Value, "0.0.0.0:") {
+			value.LineComment = insecureReceiverComment
+		}
+		markInsecureEndpoints(value)
+	}
+}
+
+// decode returns json compatible values, as the unstructured helpers require.
+func decode(data []byte) (map[string]any, error) {
+	obj := make(map[string]any)
+	if err := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(data), len(data)).Decode(&obj); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func marshal(obj any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2) //nolint: mnd
+	if err := encoder.Encode(obj); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func toAnyMap(m map[string]string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,305 @@
+package configure
+
+import (
+	"testing"
+
+	"github.com/decisiveai/mdai-cli/internal/otelconfig"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const engineTemplate = `apiVersion: mydecisive.ai/v1
+kind: MyDecisiveEngine
+metadata:
+  namespace: mdai
+  name: mydecisiveengine-sample-1
+spec:
+  telemetryModule:
+    collectors:
+      - name: gateway
+        enabled: true
+        measureVolumes: true
+        spec:
+          ingress:
+            annotations:
+              alb.ingress.kubernetes.io/scheme: internet-facing
+            collectorEndpoints:
+              otlp: otlp.example.com
+            ingressClassName: alb
+          config: |
+            receivers:
+              otlp:
+                protocols:
+                  grpc:
+                    endpoint: "0.0.0.0:4317"
+                  http:
+                    endpoint: "0.0.0.0:4318"
+            processors:
+              resource:
+                attributes:
+                - key: service.instance.id
+                  from_attribute: k8s.pod.uid
+                  action: insert
+            exporters:
+              debug:
+                verbosity: detailed
+            service:
+              pipelines:
+                traces:
+                  receivers: [otlp]
+                  processors: [resource]
+                  exporters: [debug]
+                metrics:
+                  receivers: [otlp]
+                  processors: [batch, resource]
+                  exporters: [debug]
+                logs:
+                  receivers: [otlp]
+                  processors: [resource]
+                  exporters: [debug]
+                logs/foobar:
+                  receivers: [otlp]
+                  processors: [resource]
+                  exporters: [debug]
+`
+
+type renderedCollector struct {
+	Name           string `yaml:"name"`
+	MeasureVolumes bool   `yaml:"measureVolumes"`
+	Spec           struct {
+		Ingress *struct {
+			Annotations        map[string]string `yaml:"annotations"`
+			CollectorEndpoints map[string]string `yaml:"collectorEndpoints"`
+			IngressClassName   string            `yaml:"ingressClassName"`
+		} `yaml:"ingress"`
+		Config string `yaml:"config"`
+	} `yaml:"spec"`
+}
+
+func renderedCollectors(t *testing.T, manifest []byte) []renderedCollector {
+	t.Helper()
+	var obj struct {
+		Spec struct {
+			TelemetryModule struct {
+				Collectors []renderedCollector `yaml:"collectors"`
+			} `yaml:"telemetryModule"`
+		} `yaml:"spec"`
+	}
+	require.NoError(t, yaml.Unmarshal(manifest, &obj))
+	return obj.Spec.TelemetryModule.Collectors
+}
+
+func eksAnswers() Answers {
+	return Answers{
+		ClusterProfile:     ClusterEKS,
+		CertificateARN:     "arn:aws:acm:us-east-1:012345678901:certificate/abc",
+		CollectorEndpoints: map[string]string{"otlp": "otlp.mdai.example.com"},
+		IngressClass:       "alb-internal",
+		Exporters:          []string{"debug"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers func(*Answers)
+		err     string
+	}{
+		{name: "eks"},
+		{name: "kind", answers: func(a *Answers) { *a = Answers{ClusterProfile: ClusterKind, Exporters: []string{"debug"}} }},
+		{
+			name:    "unknown profile",
+			answers: func(a *Answers) { a.ClusterProfile = "gke" },
+			err:     `invalid clusterProfile: "gke", expected one of [kind eks]`,
+		},
+		{
+			name:    "eks without certificate",
+			answers: func(a *Answers) { a.CertificateARN = "" },
+			err:     `invalid certificateArn: "", expected arn:aws:acm:REGION:ACCOUNT:certificate/ID`,
+		},
+		{
+			name:    "eks with an invalid endpoint",
+			answers: func(a *Answers) { a.CollectorEndpoints["otlp"] = "https://otlp.mdai.example.com" },
+			err:     `collectorEndpoints.otlp: invalid hostname: "https://otlp.mdai.example.com"`,
+		},
+		{
+			name:    "eks without ingress class",
+			answers: func(a *Answers) { a.IngressClass = "" },
+			err:     "ingressClass is required for eks",
+		},
+		{
+			name: "kind ignores eks settings",
+			answers: func(a *Answers) {
+				a.ClusterProfile = ClusterKind
+				a.CertificateARN = ""
+				a.IngressClass = ""
+			},
+		},
+		{
+			name:    "otlp without endpoint",
+			answers: func(a *Answers) { a.Exporters = []string{"otlp"} },
+			err:     "otlpEndpoint is required for the otlp exporter",
+		},
+		{
+			name: "several problems",
+			answers: func(a *Answers) {
+				a.CertificateARN = ""
+				a.Exporters = nil
+			},
+			err: "invalid certificateArn: \"\", expected arn:aws:acm:REGION:ACCOUNT:certificate/ID\nat least one exporter is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers := eksAnswers()
+			if tt.answers != nil {
+				tt.answers(&answers)
+			}
+			err := answers.Validate()
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateExporters(t *testing.T) {
+	tests := []struct {
+		name      string
+		exporters []string
+		err       string
+	}{
+		{name: "debug", exporters: []string{"debug"}},
+		{name: "otlp", exporters: []string{"otlp"}},
+		{name: "prometheus with otlp", exporters: []string{"prometheus", "otlp"}},
+		{name: "every exporter", exporters: Exporters()},
+		{name: "prometheus only", exporters: []string{"prometheus"}, err: "no exporter in [prometheus] can export logs"},
+		{name: "none", err: "at least one exporter is required"},
+		{name: "unknown", exporters: []string{"debug", "kafka"}, err: `invalid exporter: "kafka", expected one of [debug otlp prometheus]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExporters(tt.exporters)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEngine(t *testing.T) {
+	datalyzer := false
+	tests := []struct {
+		name      string
+		answers   Answers
+		engine    string
+		namespace string
+		check     func(t *testing.T, collector renderedCollector)
+	}{
+		{
+			name:      "kind",
+			answers:   Answers{ClusterProfile: ClusterKind, Exporters: []string{"debug"}},
+			engine:    "mydecisiveengine-sample-1",
+			namespace: "mdai",
+			check: func(t *testing.T, collector renderedCollector) {
+				require.Nil(t, collector.Spec.Ingress)
+				require.True(t, collector.MeasureVolumes)
+			},
+		},
+		{
+			name: "eks",
+			answers: func() Answers {
+				a := eksAnswers()
+				a.EngineName = "engine"
+				a.Namespace = "mdai-prod"
+				a.Datalyzer = &datalyzer
+				return a
+			}(),
+			engine:    "engine",
+			namespace: "mdai-prod",
+			check: func(t *testing.T, collector renderedCollector) {
+				require.NotNil(t, collector.Spec.Ingress)
+				require.Equal(t, map[string]string{
+					"alb.ingress.kubernetes.io/scheme":          "internet-facing",
+					"alb.ingress.kubernetes.io/certificate-arn": "arn:aws:acm:us-east-1:012345678901:certificate/abc",
+					"kubernetes.io/ingress.class":               "alb-internal",
+				}, collector.Spec.Ingress.Annotations)
+				require.Equal(t, map[string]string{"otlp": "otlp.mdai.example.com"}, collector.Spec.Ingress.CollectorEndpoints)
+				require.Equal(t, "alb-internal", collector.Spec.Ingress.IngressClassName)
+				require.False(t, collector.MeasureVolumes)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.answers.Validate())
+			engine, err := tt.answers.Engine([]byte(engineTemplate))
+			require.NoError(t, err)
+			require.Equal(t, tt.engine, engine.Name)
+			require.Equal(t, tt.namespace, engine.Namespace)
+			require.Equal(t, "gateway", engine.Collector)
+
+			collectors := renderedCollectors(t, engine.Manifest)
+			require.Len(t, collectors, 1)
+			tt.check(t, collectors[0])
+
+			cfg, err := otelconfig.Parse(collectors[0].Spec.Config)
+			require.NoError(t, err)
+			require.Empty(t, cfg.Lint())
+			require.Equal(t, []string{"logs", "metrics", "traces"}, cfg.Pipelines())
+			for _, pipeline := range cfg.Pipelines() {
+				require.Equal(t, []string{"memory_limiter", "resource", "batch"}, cfg.PipelineComponents(pipeline, otelconfig.Processors), pipeline)
+			}
+		})
+	}
+}
+
+func TestEngineExporters(t *testing.T) {
+	tests := []struct {
+		name      string
+		exporters []string
+		want      map[string][]string
+	}{
+		{
+			name:      "debug",
+			exporters: []string{"debug"},
+			want:      map[string][]string{"logs": {"debug"}, "metrics": {"debug"}, "traces": {"debug"}},
+		},
+		{
+			name:      "otlp and prometheus",
+			exporters: []string{"otlp", "prometheus"},
+			want:      map[string][]string{"logs": {"otlp"}, "metrics": {"otlp", "prometheus"}, "traces": {"otlp"}},
+		},
+		{
+			name:      "every exporter",
+			exporters: Exporters(),
+			want:      map[string][]string{"logs": {"debug", "otlp"}, "metrics": {"debug", "otlp", "prometheus"}, "traces": {"debug", "otlp"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers := Answers{ClusterProfile: ClusterKind, Exporters: tt.exporters, OTLPEndpoint: "otel.example.com:4317"}
+			require.NoError(t, answers.Validate())
+			engine, err := answers.Engine([]byte(engineTemplate))
+			require.NoError(t, err)
+			cfg, err := otelconfig.Parse(renderedCollectors(t, engine.Manifest)[0].Spec.Config)
+			require.NoError(t, err)
+
+			require.ElementsMatch(t, tt.exporters, cfg.Components(otelconfig.Exporters))
+			for signal, want := range tt.want {
+				require.Equal(t, want, cfg.PipelineComponents(signal, otelconfig.Exporters), signal)
+			}
+			require.Empty(t, cfg.Lint())
+		})
+	}
+}
+
+func TestEngineWithoutCollectors(t *testing.T) {
+	answers := Answers{ClusterProfile: ClusterKind, Exporters: []string{"debug"}}
+	_, err := answers.Engine([]byte("apiVersion: mydecisive.ai/v1\nkind: MyDecisiveEngine\nspec:\n  telemetryModule:\n    collectors: []\n"))
+	require.EqualError(t, err, "engine template has no collectors")
+}
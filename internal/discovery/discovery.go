@@ -0,0 +1,142 @@
+package discovery
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
+	"github.com/decisiveai/mdai-cli/internal/operator"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// partOf labels MDAI workloads that are not in the release manifest.
+var partOf = []string{operator.PartOf, "opentelemetry"}
+
+type Workload struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Labels    map[string]string
+	Selector  *metav1.LabelSelector
+	Images    []string
+	Desired   int32
+	Ready     int32
+	Found     bool
+}
+
+func (w Workload) Chart() (string, string) {
+	return mdaihelm.ParseChartLabel(w.Labels[mdaihelm.ChartLabel])
+}
+
+type key struct {
+	kind, namespace, name string
+}
+
+// Workloads returns the release workloads, Found unset when missing, plus
+// those labelled as part of MDAI in the namespace.
+func Workloads(ctx context.Context, helper *kubehelper.Helper, resources []mdaihelm.Resource) ([]Workload, error) {
+	return mergeWorkloads(helper.Namespace(), resources, func(namespace string) ([]Workload, error) {
+		return listWorkloads(ctx, helper, namespace)
+	})
+}
+
+func mergeWorkloads(mdaiNamespace string, resources []mdaihelm.Resource, list func(namespace string) ([]Workload, error)) ([]Workload, error) {
+	expected := map[key]bool{}
+	namespaces := []string{mdaiNamespace}
+	for _, resource := range resources {
+		switch resource.Kind {
+		case "Deployment", "StatefulSet", "DaemonSet":
+			expected[key{resource.Kind, resource.Namespace(), resource.Name()}] = true
+			if !slices.Contains(namespaces, resource.Namespace()) {
+				namespaces = append(namespaces, resource.Namespace())
+			}
+		}
+	}
+
+	var workloads []Workload
+	for _, namespace := range namespaces {
+		found, err := list(namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, workload := range found {
+			k := key{workload.Kind, workload.Namespace, workload.Name}
+			if expected[k] || (namespace == mdaiNamespace && slices.Contains(partOf, workload.Labels[operator.PartOfLabel])) {
+				workloads = append(workloads, workload)
+				delete(expected, k)
+			}
+		}
+	}
+	for k := range expected {
+		workloads = append(workloads, Workload{Kind: k.kind, Name: k.name, Namespace: k.namespace})
+	}
+
+	slices.SortFunc(workloads, func(a, b Workload) int {
+		return cmp.Or(
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return workloads, nil
+}
+
+func listWorkloads(ctx context.Context, helper *kubehelper.Helper, namespace string) ([]Workload, error) {
+	var workloads []Workload
+
+	deployments, err := helper.ListDeployments(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		var desired int32 = 1
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		workloads = append(workloads, newWorkload("Deployment", d.ObjectMeta, d.Spec.Selector, d.Spec.Template, desired, d.Status.AvailableReplicas))
+	}
+
+	statefulSets, err := helper.ListStatefulSets(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		var desired int32 = 1
+		if s.Spec.Replicas != nil {
+			desired = *s.Spec.Replicas
+		}
+		workloads = append(workloads, newWorkload("StatefulSet", s.ObjectMeta, s.Spec.Selector, s.Spec.Template, desired, s.Status.ReadyReplicas))
+	}
+
+	daemonSets, err := helper.ListDaemonSets(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for _, d := range daemonSets.Items {
+		workloads = append(workloads, newWorkload("DaemonSet", d.ObjectMeta, d.Spec.Selector, d.Spec.Template, d.Status.DesiredNumberScheduled, d.Status.NumberReady))
+	}
+
+	return workloads, nil
+}
+
+func newWorkload(kind string, meta metav1.ObjectMeta, selector *metav1.LabelSelector, template corev1.PodTemplateSpec, desired, ready int32) Workload {
+	images := make([]string, 0, len(template.Spec.Containers))
+	for _, container := range template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	return Workload{
+		Kind:      kind,
+		Name:      meta.Name,
+		Namespace: meta.Namespace,
+		Labels:    meta.Labels,
+		Selector:  selector,
+		Images:    images,
+		Desired:   desired,
+		Ready:     ready,
+		Found:     true,
+	}
+}
@@ -0,0 +1,97 @@
+package discovery
+
+import (
+	"errors"
+	"testing"
+
+	mdaihelm "github.com/decisiveai/mdai-cli/internal/helm"
+	"github.com/decisiveai/mdai-cli/internal/operator"
+	"github.com/stretchr/testify/require"
+)
+
+func resource(kind, namespace, name string) mdaihelm.Resource {
+	var r mdaihelm.Resource
+	r.Kind = kind
+	r.Metadata.Namespace = namespace
+	r.Metadata.Name = name
+	return r
+}
+
+func TestMergeWorkloads(t *testing.T) {
+	partOf := map[string]string{operator.PartOfLabel: operator.PartOf}
+	cluster := map[string][]Workload{
+		"mdai": {
+			{Kind: "Deployment", Name: "operator", Namespace: "mdai", Found: true},
+			{Kind: "StatefulSet", Name: "prometheus", Namespace: "mdai", Found: true},
+			{Kind: "Deployment", Name: "collector", Namespace: "mdai", Labels: partOf, Found: true},
+			{Kind: "DaemonSet", Name: "agent", Namespace: "mdai", Labels: map[string]string{operator.PartOfLabel: "opentelemetry"}, Found: true},
+			{Kind: "Deployment", Name: "unrelated", Namespace: "mdai", Found: true},
+		},
+		"monitoring": {
+			{Kind: "Deployment", Name: "grafana", Namespace: "monitoring", Found: true},
+			{Kind: "Deployment", Name: "collector", Namespace: "monitoring", Labels: partOf, Found: true},
+		},
+	}
+	list := func(namespace string) ([]Workload, error) {
+		return cluster[namespace], nil
+	}
+
+	tests := []struct {
+		name      string
+		resources []mdaihelm.Resource
+		want      []Workload
+	}{
+		{
+			name: "labelled only",
+			want: []Workload{
+				cluster["mdai"][3],
+				cluster["mdai"][2],
+			},
+		},
+		{
+			name: "release and labelled",
+			resources: []mdaihelm.Resource{
+				resource("Deployment", "mdai", "operator"),
+				resource("StatefulSet", "mdai", "prometheus"),
+				resource("Deployment", "monitoring", "grafana"),
+				resource("Service", "mdai", "operator"),
+			},
+			want: []Workload{
+				cluster["mdai"][3],
+				cluster["mdai"][2],
+				cluster["mdai"][0],
+				cluster["mdai"][1],
+				cluster["monitoring"][0],
+			},
+		},
+		{
+			name: "missing",
+			resources: []mdaihelm.Resource{
+				resource("Deployment", "mdai", "operator"),
+				resource("StatefulSet", "mdai", "valkey"),
+				resource("DaemonSet", "logging", "fluent-bit"),
+			},
+			want: []Workload{
+				{Kind: "DaemonSet", Name: "fluent-bit", Namespace: "logging"},
+				cluster["mdai"][3],
+				cluster["mdai"][2],
+				cluster["mdai"][0],
+				{Kind: "StatefulSet", Name: "valkey", Namespace: "mdai"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workloads, err := mergeWorkloads("mdai", tt.resources, list)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, workloads)
+		})
+	}
+}
+
+func TestMergeWorkloadsListError(t *testing.T) {
+	_, err := mergeWorkloads("mdai", nil, func(string) ([]Workload, error) {
+		return nil, errors.New("failed to list deployments: forbidden")
+	})
+	require.EqualError(t, err, "failed to list deployments: forbidden")
+}
@@ -1,34 +1,160 @@
 package editor
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/shlex"
 )
 
-type editorFinishedMsg struct{ err error }
+const defaultEditor = "vim"
+
+// Editor is an editor command with its arguments, as set in $VISUAL or
+// $EDITOR, for example "code -w".
+type Editor struct {
+	Command string
+	Args    []string
+}
+
+// strategy builds the editor arguments, line is 0 when there is none.
+type strategy func(args []string, filename string, line int) []string
+
+var strategies = map[string]strategy{
+	"vi":            plusLine,
+	"vim":           plusLine,
+	"nvim":          plusLine,
+	"gvim":          withWait(plusLine, "-f"),
+	"nano":          plusLine,
+	"pico":          plusLine,
+	"emacs":         plusLine,
+	"emacsclient":   plusLine,
+	"micro":         plusLine,
+	"kak":           plusLine,
+	"joe":           plusLine,
+	"hx":            colonLine,
+	"helix":         colonLine,
+	"code":          withWait(gotoLine, "--wait", "-w"),
+	"code-insiders": withWait(gotoLine, "--wait", "-w"),
+	"codium":        withWait(gotoLine, "--wait", "-w"),
+	"cursor":        withWait(gotoLine, "--wait", "-w"),
+	"subl":          withWait(colonLine, "--wait", "-w"),
+	"zed":           withWait(colonLine, "--wait"),
+	"mate":          withWait(flagLine("-l"), "--wait", "-w"),
+	"idea":          withWait(flagLine("--line"), "--wait"),
+	"goland":        withWait(flagLine("--line"), "--wait"),
+}
+
+func plusLine(args []string, filename string, line int) []string {
+	if line > 0 {
+		args = append(args, "+"+strconv.Itoa(line))
+	}
+	return append(args, filename)
+}
+
+func colonLine(args []string, filename string, line int) []string {
+	if line > 0 {
+		return append(args, fmt.Sprintf("%s:%d", filename, line))
+	}
+	return append(args, filename)
+}
+
+func gotoLine(args []string, filename string, line int) []string {
+	if line > 0 {
+		return append(args, "--goto", fmt.Sprintf("%s:%d", filename, line))
+	}
+	return append(args, filename)
+}
 
-func openEditor(filename, block, phase string) tea.Cmd {
-	args := []string{filename}
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vim"
+func flagLine(flag string) strategy {
+	return func(args []string, filename string, line int) []string {
+		if line > 0 {
+			args = append(args, flag, strconv.Itoa(line))
+		}
+		return append(args, filename)
+	}
+}
+
+// withWait keeps gui editors in the foreground unless a wait flag is set.
+func withWait(next strategy, flags ...string) strategy {
+	return func(args []string, filename string, line int) []string {
+		if !slices.ContainsFunc(args, func(arg string) bool { return slices.Contains(flags, arg) }) {
+			args = append(args, flags[0])
+		}
+		return next(args, filename, line)
 	}
+}
+
+// FromEnv returns the editor of $VISUAL, or $EDITOR, or vim.
+func FromEnv() (Editor, error) {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		value := strings.TrimSpace(os.Getenv(env))
+		if value == "" {
+			continue
+		}
+		fields, err := shlex.Split(value)
+		if err != nil || len(fields) == 0 {
+			return Editor{}, fmt.Errorf("invalid $%s: %q", env, value)
+		}
+		return Editor{Command: fields[0], Args: fields[1:]}, nil
+	}
+	return Editor{Command: defaultEditor}, nil
+}
+
+// Cmd opens filename at line with the argument strategy of the editor, or
+// just the file for editors it does not know.
+func (e Editor) Cmd(filename string, line int) *exec.Cmd {
+	args := slices.Clone(e.Args)
+	name := strings.TrimSuffix(filepath.Base(e.Command), ".exe")
+	if s, ok := strategies[name]; ok {
+		args = s(args, filename, line)
+	} else {
+		args = append(args, filename)
+	}
+	return exec.Command(e.Command, args...) //nolint: gosec
+}
+
+// Line returns the line of a top level block, such as receivers, or of the
+// first pipeline of a phase, such as logs or logs/audit; 0 if there is none.
+func Line(data []byte, block, phase string) int {
+	var pattern *regexp.Regexp
 	switch {
 	case block != "":
-		args = append(args, "+/^"+block+":")
+		pattern = regexp.MustCompile(`^` + regexp.QuoteMeta(block) + `:`)
 	case phase != "":
-		args = append(args, "+/^ .*"+phase+":")
+		pattern = regexp.MustCompile(`^\s+` + regexp.QuoteMeta(phase) + `(/[^:]*)?:`)
+	default:
+		return 0
 	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		if pattern.Match(scanner.Bytes()) {
+			return line
+		}
+	}
+	return 0
+}
+
+type editorFinishedMsg struct{ err error }
 
-	c := exec.Command(editor, args...)
+func openEditor(editor Editor, filename, block, phase string) tea.Cmd {
+	data, _ := os.ReadFile(filename)
+	c := editor.Cmd(filename, Line(data, block, phase))
 	return tea.ExecProcess(c, func(err error) tea.Msg {
 		return editorFinishedMsg{err}
 	})
 }
 
 type model struct {
+	editor   Editor
 	filename string
 	block    string
 	phase    string
@@ -36,7 +162,7 @@ type model struct {
 }
 
 func (m model) Init() tea.Cmd {
-	return openEditor(m.filename, m.block, m.phase)
+	return openEditor(m.editor, m.filename, m.block, m.phase)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -55,6 +181,18 @@ func (m model) View() string {
 	return ""
 }
 
-func NewModel(filename string, block string, phase string) model {
-	return model{filename: filename, block: block, phase: phase}
+func NewModel(editor Editor, filename string, block string, phase string) model {
+	return model{editor: editor, filename: filename, block: block, phase: phase}
+}
+
+// Run opens filename in the editor and waits for it to exit.
+func Run(editor Editor, filename, block, phase string) error {
+	m, err := tea.NewProgram(NewModel(editor, filename, block, phase)).Run()
+	if err != nil {
+		return fmt.Errorf("failed to run editor: %w", err)
+	}
+	if err := m.(model).err; err != nil {
+		return fmt.Errorf("editor %s failed: %w", editor.Command, err)
+	}
+	return nil
 }
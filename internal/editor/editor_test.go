@@ -0,0 +1,95 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmd(t *testing.T) {
+	tests := []struct {
+		name   string
+		editor Editor
+		line   int
+		want   []string
+	}{
+		{name: "vim", editor: Editor{Command: "vim"}, line: 12, want: []string{"vim", "+12", "config.yaml"}},
+		{name: "vim without line", editor: Editor{Command: "/usr/bin/vim"}, want: []string{"/usr/bin/vim", "config.yaml"}},
+		{name: "gvim", editor: Editor{Command: "gvim"}, line: 12, want: []string{"gvim", "-f", "+12", "config.yaml"}},
+		{name: "helix", editor: Editor{Command: "hx"}, line: 12, want: []string{"hx", "config.yaml:12"}},
+		{name: "code", editor: Editor{Command: "code"}, line: 12, want: []string{"code", "--wait", "--goto", "config.yaml:12"}},
+		{name: "code with wait", editor: Editor{Command: "code", Args: []string{"-w"}}, line: 12, want: []string{"code", "-w", "--goto", "config.yaml:12"}},
+		{name: "code exe", editor: Editor{Command: "/opt/bin/code.exe"}, want: []string{"/opt/bin/code.exe", "--wait", "config.yaml"}},
+		{name: "subl", editor: Editor{Command: "subl"}, line: 12, want: []string{"subl", "--wait", "config.yaml:12"}},
+		{name: "mate", editor: Editor{Command: "mate"}, line: 12, want: []string{"mate", "--wait", "-l", "12", "config.yaml"}},
+		{name: "idea", editor: Editor{Command: "idea"}, line: 12, want: []string{"idea", "--wait", "--line", "12", "config.yaml"}},
+		{name: "unknown", editor: Editor{Command: "ed", Args: []string{"-s"}}, line: 12, want: []string{"ed", "-s", "config.yaml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.editor.Cmd("config.yaml", tt.line).Args)
+		})
+	}
+}
+
+func TestLine(t *testing.T) {
+	data := []byte(`receivers:
+  otlp:
+exporters:
+  debug:
+service:
+  pipelines:
+    logs/audit:
+      receivers: [otlp]
+    logs:
+      receivers: [otlp]
+`)
+	tests := []struct {
+		name  string
+		block string
+		phase string
+		want  int
+	}{
+		{name: "block", block: "exporters", want: 3},
+		{name: "nested key is not a block", block: "otlp"},
+		{name: "phase", phase: "logs", want: 7},
+		{name: "phase prefix", phase: "log"},
+		{name: "missing phase", phase: "traces"},
+		{name: "neither"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Line(data, tt.block, tt.phase))
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		visual string
+		editor string
+		want   Editor
+		err    string
+	}{
+		{name: "default", want: Editor{Command: "vim"}},
+		{name: "editor", editor: "nano", want: Editor{Command: "nano", Args: []string{}}},
+		{name: "visual first", visual: "code -w", editor: "nano", want: Editor{Command: "code", Args: []string{"-w"}}},
+		{name: "quoted", editor: `"/opt/my editor/bin/edit" --wait`, want: Editor{Command: "/opt/my editor/bin/edit", Args: []string{"--wait"}}},
+		{name: "blank visual", visual: "  ", editor: "nano", want: Editor{Command: "nano", Args: []string{}}},
+		{name: "invalid", editor: `"code`, err: `invalid $EDITOR: "\"code"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VISUAL", tt.visual)
+			t.Setenv("EDITOR", tt.editor)
+			editor, err := FromEnv()
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, editor)
+		})
+	}
+}
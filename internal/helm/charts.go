@@ -1,10 +1,13 @@
 package helm
 
 import (
+	"bytes"
 	"embed"
 	"fmt"
+	"text/template"
 	"time"
 
+	"github.com/decisiveai/mdai-cli/internal/config"
 	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
 	"gopkg.in/yaml.v3"
 )
@@ -16,24 +19,63 @@ var chartSpecs = map[string]mdaitypes.ChartSpec{
 	"mdai-cluster": {
 		ReleaseName:     "mdai-cluster",
 		ChartURL:        "https://github.com/DecisiveAI/mdai-helm-charts/raw/gh-pages/%s-%s.tgz",
-		Namespace:       "mdai",
 		Version:         "0.0.2",
 		Values:          map[string]any{},
 		UpgradeCRDs:     true,
 		Wait:            true,
 		Replace:         true,
 		CreateNamespace: true,
+		Atomic:          true,
+		CleanupOnFail:   true,
 		Timeout:         120 * time.Second, //nolint: mnd
 	},
+	"opentelemetry-demo": {
+		ReleaseName:     "opentelemetry-demo",
+		ChartURL:        "https://github.com/open-telemetry/opentelemetry-helm-charts/releases/download/%[1]s-%[2]s/%[1]s-%[2]s.tgz",
+		Namespace:       "opentelemetry-demo",
+		Version:         "0.32.0",
+		Values:          map[string]any{},
+		Wait:            true,
+		CreateNamespace: true,
+		Atomic:          true,
+		CleanupOnFail:   true,
+		Timeout:         300 * time.Second, //nolint: mnd
+	},
+}
+
+func ChartVersions() map[string]string {
+	versions := make(map[string]string, len(chartSpecs))
+	for _, spec := range chartSpecs {
+		versions[spec.ReleaseName] = spec.Version
+	}
+	return versions
 }
 
-func getChartSpec(name string) (*mdaitypes.ChartSpec, error) {
+type valuesData struct {
+	Namespace string
+}
+
+func getChartSpec(name, mdaiNamespace string) (*mdaitypes.ChartSpec, error) {
 	spec, ok := chartSpecs[name]
 	if !ok {
 		return nil, fmt.Errorf("chart %s not found", name)
 	}
-	valuesYaml, _ := embedFS.ReadFile("templates/" + name + "-values.yaml")
-	if err := yaml.Unmarshal(valuesYaml, &spec.Values); err != nil {
+	if mdaiNamespace == "" {
+		mdaiNamespace = config.DefaultNamespace
+	}
+	if spec.Namespace == "" {
+		spec.Namespace = mdaiNamespace
+	}
+	valuesTemplate, _ := embedFS.ReadFile("templates/" + name + "-values.yaml")
+	tmpl, err := template.New(name).Delims("[[", "]]").Option("missingkey=error").Parse(string(valuesTemplate))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse chart values %s: %w", name, err)
+	}
+	var valuesYaml bytes.Buffer
+	if err := tmpl.Execute(&valuesYaml, valuesData{Namespace: mdaiNamespace}); err != nil {
+		return nil, fmt.Errorf("failed to render chart values %s: %w", name, err)
+	}
+	if err := yaml.Unmarshal(valuesYaml.Bytes(), &spec.Values); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal chart spec %s: %w", name, err)
 	}
 	return &spec, nil
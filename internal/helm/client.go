@@ -4,22 +4,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/decisiveai/mdai-cli/internal/cache"
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
 	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
-	"golang.org/x/mod/semver"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 )
 
 type Client struct {
 	envSettings *cli.EnvSettings
 	logger      *log.Logger
+	namespace   string
+	timeout     time.Duration
+	backoff     wait.Backoff
+	chartRepo   string
+	skipVerify  bool
+	digests     map[string]string
 }
 
 type ClientOption func(*Client)
@@ -32,6 +44,21 @@ func WithContext(ctx context.Context) ClientOption {
 		if kubecontext, ok := ctx.Value(mdaitypes.Kubecontext{}).(string); ok {
 			client.envSettings.KubeContext = kubecontext
 		}
+		if namespace, ok := ctx.Value(mdaitypes.Namespace{}).(string); ok {
+			client.namespace = namespace
+		}
+		if timeout, ok := ctx.Value(mdaitypes.Timeout{}).(time.Duration); ok {
+			client.timeout = timeout
+		}
+		if retries, ok := ctx.Value(mdaitypes.Retries{}).(int); ok {
+			client.backoff = kubehelper.Backoff(retries)
+		}
+		if chartRepo, ok := ctx.Value(mdaitypes.ChartRepo{}).(string); ok {
+			client.chartRepo = chartRepo
+		}
+		if skipVerify, ok := ctx.Value(mdaitypes.InsecureSkipVerify{}).(bool); ok {
+			client.skipVerify = skipVerify
+		}
 		client.logger = log.FromContext(ctx)
 	}
 }
@@ -39,6 +66,7 @@ func WithContext(ctx context.Context) ClientOption {
 func NewClient(options ...ClientOption) *Client {
 	client := new(Client)
 	client.envSettings = cli.New()
+	client.backoff = kubehelper.Backoff(kubehelper.DefaultRetries)
 	for _, option := range options {
 		option(client)
 	}
@@ -47,7 +75,7 @@ func NewClient(options ...ClientOption) *Client {
 }
 
 func (c *Client) InstallChart(helmchart string) error {
-	chartSpec, err := getChartSpec(helmchart)
+	chartSpec, err := c.getChartSpec(helmchart)
 	if err != nil {
 		return fmt.Errorf("failed to get chart spec: %w", err)
 	}
@@ -57,13 +85,16 @@ func (c *Client) InstallChart(helmchart string) error {
 		return fmt.Errorf("failed to get action config: %w", err)
 	}
 
-	helmChart, err := loadChart(fmt.Sprintf(chartSpec.ChartURL, chartSpec.ReleaseName, chartSpec.Version), settings)
+	helmChart, err := c.loadChart(chartSpec, settings)
 	if err != nil {
 		return fmt.Errorf("failed to load chart: %w", err)
 	}
 
-	getClient := action.NewGet(actionConfig)
-	helmRelease, err := getClient.Run(chartSpec.ReleaseName)
+	helmRelease, err := action.NewGet(actionConfig).Run(chartSpec.ReleaseName)
+	if err == nil && isIncomplete(helmRelease) {
+		return fmt.Errorf("release %s in namespace %s is %s since %s (revision %d), another install or upgrade may still be running; wait for it, or roll it back with mdai rollback-release",
+			chartSpec.ReleaseName, chartSpec.Namespace, helmRelease.Info.Status, helmRelease.Info.LastDeployed.Format(time.RFC3339), helmRelease.Version)
+	}
 	if errors.Is(err, driver.ErrReleaseNotFound) {
 		installClient := action.NewInstall(actionConfig)
 		installClient.ReleaseName = chartSpec.ReleaseName
@@ -71,28 +102,90 @@ func (c *Client) InstallChart(helmchart string) error {
 		installClient.CreateNamespace = chartSpec.CreateNamespace
 		installClient.Wait = chartSpec.Wait
 		installClient.Timeout = chartSpec.Timeout
+		installClient.Atomic = chartSpec.Atomic
 
-		if _, err = installClient.Run(helmChart, chartSpec.Values); err != nil {
+		if err = c.retry(func() error {
+			_, err := installClient.Run(helmChart, chartSpec.Values)
+			if err != nil {
+				c.recoverFailed(actionConfig, chartSpec)
+			}
+			return err
+		}); err != nil {
 			return fmt.Errorf("failed to install chart %s in namespace %s: %w", chartSpec.ReleaseName, chartSpec.Namespace, err)
 		}
 		return nil
 	}
+	if err != nil {
+		return fmt.Errorf("failed to get release %s in namespace %s: %w", chartSpec.ReleaseName, chartSpec.Namespace, err)
+	}
 
-	if semver.Compare(helmRelease.Chart.Metadata.Version, chartSpec.Version) < 0 {
+	if compareVersions(helmRelease.Chart.Metadata.Version, chartSpec.Version) < 0 {
 		upgradeClient := action.NewUpgrade(actionConfig)
 		upgradeClient.Namespace = chartSpec.Namespace
 		upgradeClient.Wait = chartSpec.Wait
 		upgradeClient.Timeout = chartSpec.Timeout
+		upgradeClient.Atomic = chartSpec.Atomic
+		upgradeClient.CleanupOnFail = chartSpec.CleanupOnFail
 
-		if _, err = upgradeClient.Run(chartSpec.ReleaseName, helmChart, chartSpec.Values); err != nil {
+		if err = c.retry(func() error {
+			_, err := upgradeClient.Run(chartSpec.ReleaseName, helmChart, chartSpec.Values)
+			if err != nil {
+				c.recoverFailed(actionConfig, chartSpec)
+			}
+			return err
+		}); err != nil {
 			return fmt.Errorf("failed to upgrade chart %s in namespace %s: %w", chartSpec.ReleaseName, chartSpec.Namespace, err)
 		}
 	}
 	return nil
 }
 
+func (c *Client) RollbackChart(helmchart string, revision int) error {
+	chartSpec, err := c.getChartSpec(helmchart)
+	if err != nil {
+		return fmt.Errorf("failed to get chart spec: %w", err)
+	}
+
+	actionConfig, _, err := c.getActionConfig(chartSpec.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get action config: %w", err)
+	}
+
+	history, err := action.NewHistory(actionConfig).Run(chartSpec.ReleaseName)
+	if err != nil {
+		return fmt.Errorf("failed to get history of release %s in namespace %s: %w", chartSpec.ReleaseName, chartSpec.Namespace, err)
+	}
+	if !slices.ContainsFunc(history, func(rel *release.Release) bool { return rel.Version == revision }) {
+		return fmt.Errorf("revision %d not found in history of release %s", revision, chartSpec.ReleaseName)
+	}
+
+	if err := rollback(actionConfig, chartSpec, revision); err != nil {
+		return fmt.Errorf("failed to roll back release %s in namespace %s to revision %d: %w", chartSpec.ReleaseName, chartSpec.Namespace, revision, err)
+	}
+	return nil
+}
+
+func (c *Client) History(helmchart string) ([]*release.Release, error) {
+	chartSpec, err := c.getChartSpec(helmchart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chart spec: %w", err)
+	}
+
+	actionConfig, _, err := c.getActionConfig(chartSpec.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action config: %w", err)
+	}
+
+	history, err := action.NewHistory(actionConfig).Run(chartSpec.ReleaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history of release %s in namespace %s: %w", chartSpec.ReleaseName, chartSpec.Namespace, err)
+	}
+	slices.SortFunc(history, func(a, b *release.Release) int { return b.Version - a.Version })
+	return history, nil
+}
+
 func (c *Client) UninstallChart(helmchart string) error {
-	chartSpec, err := getChartSpec(helmchart)
+	chartSpec, err := c.getChartSpec(helmchart)
 	if err != nil {
 		return fmt.Errorf("failed to get chart spec: %w", err)
 	}
@@ -109,57 +202,85 @@ func (c *Client) UninstallChart(helmchart string) error {
 	}
 
 	uninstallClient := action.NewUninstall(actionConfig)
-	if _, err := uninstallClient.Run(chartSpec.ReleaseName); err != nil {
+	uninstallClient.Wait = chartSpec.Wait
+	uninstallClient.Timeout = chartSpec.Timeout
+	if err := c.retry(func() error {
+		_, err := uninstallClient.Run(chartSpec.ReleaseName)
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil
+		}
+		return err
+	}); err != nil {
 		return fmt.Errorf("failed to uninstall chart %s in namespace %s: %w", chartSpec.ReleaseName, chartSpec.Namespace, err)
 	}
 
 	return nil
 }
 
-func (c *Client) Outdated() ([][]string, error) {
-	releases, err := c.Releases()
+func (c *Client) ChartNamespace(helmchart string) (string, error) {
+	chartSpec, err := c.getChartSpec(helmchart)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("failed to get chart spec: %w", err)
 	}
+	return chartSpec.Namespace, nil
+}
 
-	var rows [][]string
-	expectedCharts := []string{"prometheus", "opentelemetry-operator", "mydecisive-engine-operator", "mdai-console", "datalyzer"}
-	seenCharts := make(map[string]bool, len(expectedCharts))
+func (c *Client) Release(helmchart string) (*release.Release, error) {
+	chartSpec, err := c.getChartSpec(helmchart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chart spec: %w", err)
+	}
 
-	for _, rel := range releases {
-		chartSpec, err := getChartSpec(rel.Name)
-		if chartSpec == nil || err != nil {
-			continue
-		}
-		seenCharts[rel.Name] = true
-		var outdated string
-		current := rel.Chart.Metadata.Version
-		if !strings.HasPrefix(current, "v") {
-			current = "v" + current
-		}
-		wanted := chartSpec.Version
-		if !strings.HasPrefix(wanted, "v") {
-			wanted = "v" + wanted
-		}
-		switch semver.Compare(current, wanted) {
-		case -1: // v < w
-			outdated = "✗"
-		case 0, 1: // v == w, v > w
-			outdated = "✓"
-		}
-		rows = append(rows, []string{outdated, rel.Name, current, wanted})
+	actionConfig, _, err := c.getActionConfig(chartSpec.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action config: %w", err)
 	}
 
-	for _, rel := range expectedCharts {
-		if ok := seenCharts[rel]; !ok {
-			chartSpec, err := getChartSpec(rel)
-			if chartSpec == nil || err != nil {
-				continue
-			}
-			rows = append(rows, []string{"✗", rel, "", "v" + chartSpec.Version})
-		}
+	helmRelease, err := action.NewGet(actionConfig).Run(chartSpec.ReleaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release %s in namespace %s: %w", chartSpec.ReleaseName, chartSpec.Namespace, err)
+	}
+	return helmRelease, nil
+}
+
+func (c *Client) UpgradeValues(helmchart string, values map[string]any) error {
+	chartSpec, err := c.getChartSpec(helmchart)
+	if err != nil {
+		return fmt.Errorf("failed to get chart spec: %w", err)
+	}
+
+	actionConfig, _, err := c.getActionConfig(chartSpec.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get action config: %w", err)
+	}
+
+	helmRelease, err := action.NewGet(actionConfig).Run(chartSpec.ReleaseName)
+	if err != nil {
+		return fmt.Errorf("failed to get release %s in namespace %s: %w", chartSpec.ReleaseName, chartSpec.Namespace, err)
+	}
+
+	upgradeClient := action.NewUpgrade(actionConfig)
+	upgradeClient.Namespace = chartSpec.Namespace
+	upgradeClient.Wait = chartSpec.Wait
+	upgradeClient.Timeout = chartSpec.Timeout
+	upgradeClient.Atomic = chartSpec.Atomic
+	upgradeClient.CleanupOnFail = chartSpec.CleanupOnFail
+
+	if err = c.retry(func() error {
+		_, err := upgradeClient.Run(chartSpec.ReleaseName, helmRelease.Chart, values)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to upgrade values of chart %s in namespace %s: %w", chartSpec.ReleaseName, chartSpec.Namespace, err)
+	}
+	return nil
+}
+
+func (c *Client) ReleaseResources(helmchart string) ([]Resource, error) {
+	helmRelease, err := c.Release(helmchart)
+	if err != nil {
+		return nil, err
 	}
-	return rows, nil
+	return parseManifest(helmRelease.Manifest, helmRelease.Namespace), nil
 }
 
 func (c *Client) Releases() ([]*release.Release, error) {
@@ -178,11 +299,77 @@ func (c *Client) Releases() ([]*release.Release, error) {
 	return releases, nil
 }
 
-func loadChart(chartURL string, settings *cli.EnvSettings) (*chart.Chart, error) {
-	chartPath, err := (&action.ChartPathOptions{}).LocateChart(chartURL, settings)
+// recoverFailed leaves pending releases to whoever is still running them.
+func (c *Client) recoverFailed(actionConfig *action.Configuration, chartSpec *mdaitypes.ChartSpec) {
+	helmRelease, err := action.NewGet(actionConfig).Run(chartSpec.ReleaseName)
+	if err != nil || helmRelease.Info.Status != release.StatusFailed {
+		return
+	}
+	if _, err := c.recoverRelease(actionConfig, chartSpec, helmRelease); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		c.logger.Warnf("failed to recover release %s in namespace %s: %v", chartSpec.ReleaseName, chartSpec.Namespace, err)
+	}
+}
+
+func (c *Client) recoverRelease(actionConfig *action.Configuration, chartSpec *mdaitypes.ChartSpec, helmRelease *release.Release) (*release.Release, error) {
+	history, err := action.NewHistory(actionConfig).Run(chartSpec.ReleaseName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to locate chart: %w", err)
+		return nil, fmt.Errorf("failed to get history: %w", err)
 	}
+	revision := lastDeployedRevision(history)
+	if revision == 0 {
+		c.logger.Debugf("uninstalling incomplete release %s (revision %d, %s)", helmRelease.Name, helmRelease.Version, helmRelease.Info.Status)
+		if _, err := action.NewUninstall(actionConfig).Run(chartSpec.ReleaseName); err != nil {
+			return nil, fmt.Errorf("failed to uninstall: %w", err)
+		}
+		return nil, driver.ErrReleaseNotFound
+	}
+
+	c.logger.Debugf("rolling back incomplete release %s (revision %d, %s) to revision %d", helmRelease.Name, helmRelease.Version, helmRelease.Info.Status, revision)
+	if err := rollback(actionConfig, chartSpec, revision); err != nil {
+		return nil, fmt.Errorf("failed to roll back to revision %d: %w", revision, err)
+	}
+	return action.NewGet(actionConfig).Run(chartSpec.ReleaseName)
+}
+
+func rollback(actionConfig *action.Configuration, chartSpec *mdaitypes.ChartSpec, revision int) error {
+	rollbackClient := action.NewRollback(actionConfig)
+	rollbackClient.Version = revision
+	rollbackClient.Wait = chartSpec.Wait
+	rollbackClient.Timeout = chartSpec.Timeout
+	rollbackClient.CleanupOnFail = chartSpec.CleanupOnFail
+	return rollbackClient.Run(chartSpec.ReleaseName)
+}
+
+func isIncomplete(helmRelease *release.Release) bool {
+	return helmRelease.Info.Status == release.StatusFailed || helmRelease.Info.Status.IsPending()
+}
+
+func lastDeployedRevision(history []*release.Release) int {
+	var revision int
+	for _, rel := range history {
+		if rel.Info.Status != release.StatusDeployed && rel.Info.Status != release.StatusSuperseded {
+			continue
+		}
+		revision = max(revision, rel.Version)
+	}
+	return revision
+}
+
+func (c *Client) loadChart(chartSpec *mdaitypes.ChartSpec, settings *cli.EnvSettings) (*chart.Chart, error) {
+	return c.load(chartSpec, settings, true)
+}
+
+// inspectChart skips verification, never install a chart loaded this way.
+func (c *Client) inspectChart(chartSpec *mdaitypes.ChartSpec, settings *cli.EnvSettings) (*chart.Chart, error) {
+	return c.load(chartSpec, settings, false)
+}
+
+func (c *Client) load(chartSpec *mdaitypes.ChartSpec, settings *cli.EnvSettings, verify bool) (*chart.Chart, error) {
+	chartPath, cleanup, err := c.locateChart(chartSpec, settings, verify)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
 	helmChart, err := loader.Load(chartPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load chart: %w", err)
@@ -190,6 +377,104 @@ func loadChart(chartURL string, settings *cli.EnvSettings) (*chart.Chart, error)
 	return helmChart, nil
 }
 
+// locateChart returns a cleanup that removes an uncached download once loaded.
+func (c *Client) locateChart(chartSpec *mdaitypes.ChartSpec, settings *cli.EnvSettings, verify bool) (string, func(), error) {
+	noCleanup := func() {}
+	ref := chartSpec.ChartURL
+	if c.chartRepo != "" {
+		ref = c.chartRepo
+	}
+	source, err := NewChartSource(ref, settings)
+	if err != nil {
+		return "", noCleanup, fmt.Errorf("failed to get chart source: %w", err)
+	}
+	_, local := source.(*localSource)
+
+	if entry, err := cache.Lookup(chartSpec.ReleaseName, chartSpec.Version, chartSpec.Digest, source.String()); err == nil && !local {
+		err := c.checkChart(verify, entry.Path, chartSpec)
+		if err == nil {
+			c.logger.Debugf("using cached chart %s", entry.Path)
+			return entry.Path, noCleanup, nil
+		}
+		c.logger.Debugf("not using cached chart %s: %v", entry.Path, err)
+	}
+
+	c.logger.Debugf("locating chart %s-%s in %s", chartSpec.ReleaseName, chartSpec.Version, source)
+	chartPath, err := source.Locate(chartSpec.ReleaseName, chartSpec.Version)
+	if err != nil {
+		return "", noCleanup, fmt.Errorf("failed to locate chart in %s: %w", source, err)
+	}
+	if local {
+		c.logger.Debugf("using local chart %s without verification", chartPath)
+		return chartPath, noCleanup, nil
+	}
+
+	cleanup := func() { _ = os.RemoveAll(filepath.Dir(chartPath)) }
+	if err := c.checkChart(verify, chartPath, chartSpec); err != nil {
+		cleanup()
+		return "", noCleanup, err
+	}
+	entry, err := cache.Store(chartSpec.ReleaseName, chartSpec.Version, source.String(), chartPath)
+	if err != nil {
+		c.logger.Warnf("failed to cache chart %s-%s: %v", chartSpec.ReleaseName, chartSpec.Version, err)
+		return chartPath, cleanup, nil
+	}
+	cleanup()
+	return entry.Path, noCleanup, nil
+}
+
+func (c *Client) checkChart(verify bool, chartPath string, chartSpec *mdaitypes.ChartSpec) error {
+	if !verify {
+		return nil
+	}
+	return c.verifyChart(chartPath, chartSpec)
+}
+
+func (c *Client) verifyChart(chartPath string, chartSpec *mdaitypes.ChartSpec) error {
+	if c.skipVerify {
+		c.logger.Warnf("skipping verification of chart %s-%s", chartSpec.ReleaseName, chartSpec.Version)
+		return nil
+	}
+	if chartSpec.Digest != "" {
+		return verifyChart(chartPath, chartSpec)
+	}
+
+	var digestErr error
+	if c.digests == nil {
+		c.digests, digestErr = fetchChartDigests(context.Background())
+	}
+	spec := *chartSpec
+	spec.Digest = c.digests[spec.ReleaseName+"-"+spec.Version]
+	if err := verifyChart(chartPath, &spec); err != nil {
+		if digestErr != nil {
+			return fmt.Errorf("%w (%w)", err, digestErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *Client) getChartSpec(name string) (*mdaitypes.ChartSpec, error) {
+	chartSpec, err := getChartSpec(name, c.namespace)
+	if err != nil {
+		return nil, err
+	}
+	if c.timeout > 0 {
+		chartSpec.Timeout = c.timeout
+	}
+	return chartSpec, nil
+}
+
+func (c *Client) retry(fn func() error) error {
+	return retry.OnError(c.backoff, kubehelper.IsTransient, func() error {
+		err := fn()
+		if err != nil && kubehelper.IsTransient(err) {
+			c.logger.Debugf("retrying after transient error: %v", err)
+		}
+		return err
+	})
+}
+
 func (c *Client) getActionConfig(namespace string) (*action.Configuration, *cli.EnvSettings, error) {
 	settings := c.envSettings
 	settings.SetNamespace(namespace)
@@ -0,0 +1,88 @@
+package helm
+
+import (
+	"cmp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+const ChartLabel = "helm.sh/chart"
+
+type Resource struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name      string            `yaml:"name"`
+		Namespace string            `yaml:"namespace"`
+		Labels    map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+}
+
+func (r Resource) Name() string {
+	return r.Metadata.Name
+}
+
+func (r Resource) Namespace() string {
+	return r.Metadata.Namespace
+}
+
+func (r Resource) Labels() map[string]string {
+	return r.Metadata.Labels
+}
+
+func (r Resource) String() string {
+	if r.Metadata.Namespace == "" {
+		return r.Kind + " " + r.Metadata.Name
+	}
+	return r.Kind + " " + r.Metadata.Namespace + "/" + r.Metadata.Name
+}
+
+// ParseChartLabel splits "mdai-cluster-0.0.2-rc1" at the first dash followed
+// by a digit.
+func ParseChartLabel(label string) (string, string) {
+	for i := 0; i < len(label)-1; i++ {
+		if label[i] == '-' && label[i+1] >= '0' && label[i+1] <= '9' {
+			return label[:i], label[i+1:]
+		}
+	}
+	return label, ""
+}
+
+func parseManifest(manifest, namespace string) []Resource {
+	var resources []Resource
+	for _, doc := range releaseutil.SplitManifests(manifest) {
+		var resource Resource
+		if err := yaml.Unmarshal([]byte(doc), &resource); err != nil || resource.Kind == "" {
+			continue
+		}
+		if resource.Metadata.Namespace == "" && !isClusterScoped(resource.Kind) {
+			resource.Metadata.Namespace = namespace
+		}
+		resources = append(resources, resource)
+	}
+	slices.SortFunc(resources, func(a, b Resource) int {
+		return cmp.Or(
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Metadata.Namespace, b.Metadata.Namespace),
+			cmp.Compare(a.Metadata.Name, b.Metadata.Name),
+		)
+	})
+	return resources
+}
+
+func isClusterScoped(kind string) bool {
+	return slices.Contains([]string{
+		"APIService",
+		"ClusterRole",
+		"ClusterRoleBinding",
+		"CustomResourceDefinition",
+		"MutatingWebhookConfiguration",
+		"Namespace",
+		"PersistentVolume",
+		"PriorityClass",
+		"StorageClass",
+		"ValidatingWebhookConfiguration",
+	}, kind)
+}
@@ -0,0 +1,221 @@
+package helm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+const (
+	outdatedMark = "✗"
+	upToDateMark = "✓"
+)
+
+// Outdated compares installed releases with the target charts, their
+// Chart.lock subcharts and rendered images.
+func (c *Client) Outdated() ([][]string, error) {
+	releases, err := c.Releases()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	seen := map[string]bool{}
+	for _, rel := range releases {
+		chartSpec, err := c.getChartSpec(rel.Name)
+		if err != nil {
+			continue
+		}
+		seen[rel.Name] = true
+		rows = append(rows, versionRow(rel.Name, "chart", rel.Chart.Metadata.Version, chartSpec.Version))
+
+		subRows, err := c.outdatedComponents(rel, chartSpec)
+		if err != nil {
+			c.logger.Warnf("not comparing subcharts and images of %s: %v", rel.Name, err)
+			continue
+		}
+		rows = append(rows, subRows...)
+	}
+
+	if !seen["mdai-cluster"] {
+		chartSpec, err := c.getChartSpec("mdai-cluster")
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, versionRow("mdai-cluster", "chart", "", chartSpec.Version))
+	}
+	return rows, nil
+}
+
+func (c *Client) outdatedComponents(rel *release.Release, chartSpec *mdaitypes.ChartSpec) ([][]string, error) {
+	_, settings, err := c.getActionConfig(chartSpec.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action config: %w", err)
+	}
+	target, err := c.inspectChart(chartSpec, settings)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := renderManifest(target, chartSpec.Values, rel.Name, rel.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	deployed := manifestCharts(rel.Manifest, rel.Chart.Metadata.Name)
+	enabled := manifestCharts(rendered, target.Metadata.Name)
+	wanted := wantedCharts(lockedCharts(target), enabled)
+	for _, name := range unionKeys(deployed, wanted) {
+		rows = append(rows, versionRow(name, "subchart", deployed[name], wanted[name]))
+	}
+
+	deployedImages := manifestImages(rel.Manifest)
+	wantedImages := manifestImages(rendered)
+	for _, name := range unionKeys(deployedImages, wantedImages) {
+		mark := upToDateMark
+		if deployedImages[name] != wantedImages[name] {
+			mark = outdatedMark
+		}
+		rows = append(rows, []string{mark, name, "image", deployedImages[name], wantedImages[name]})
+	}
+	return rows, nil
+}
+
+func versionRow(name, kind, current, wanted string) []string {
+	mark := upToDateMark
+	if current == "" || wanted == "" || compareVersions(current, wanted) < 0 {
+		mark = outdatedMark
+	}
+	return []string{mark, name, kind, current, wanted}
+}
+
+func compareVersions(a, b string) int {
+	if !strings.HasPrefix(a, "v") {
+		a = "v" + a
+	}
+	if !strings.HasPrefix(b, "v") {
+		b = "v" + b
+	}
+	if !semver.IsValid(a) || !semver.IsValid(b) {
+		if a == b {
+			return 0
+		}
+		return -1
+	}
+	return semver.Compare(a, b)
+}
+
+func renderManifest(ch *chart.Chart, values map[string]any, name, namespace string) (string, error) {
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	install.ReleaseName = name
+	install.Namespace = namespace
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	rel, err := install.Run(ch, values)
+	if err != nil {
+		return "", fmt.Errorf("failed to render chart %s: %w", ch.Metadata.Name, err)
+	}
+	return rel.Manifest, nil
+}
+
+// lockedCharts falls back to the packaged subcharts without a Chart.lock.
+func lockedCharts(ch *chart.Chart) map[string]string {
+	charts := map[string]string{}
+	if ch.Lock != nil {
+		for _, dependency := range ch.Lock.Dependencies {
+			charts[dependency.Name] = dependency.Version
+		}
+		return charts
+	}
+	for _, dependency := range ch.Dependencies() {
+		charts[dependency.Metadata.Name] = dependency.Metadata.Version
+	}
+	return charts
+}
+
+// wantedCharts drops locked subcharts that the target values disable.
+func wantedCharts(locked, enabled map[string]string) map[string]string {
+	wanted := map[string]string{}
+	for name, version := range locked {
+		if _, ok := enabled[name]; ok {
+			wanted[name] = version
+		}
+	}
+	return wanted
+}
+
+// manifestCharts reads the helm.sh/chart labels of everything but umbrella.
+func manifestCharts(manifest, umbrella string) map[string]string {
+	charts := map[string]string{}
+	for _, resource := range parseManifest(manifest, "") {
+		name, version := ParseChartLabel(resource.Labels()[ChartLabel])
+		if name != "" && name != umbrella {
+			charts[name] = version
+		}
+	}
+	return charts
+}
+
+// manifestImages maps kind/name/container to its image.
+func manifestImages(manifest string) map[string]string {
+	images := map[string]string{}
+	for _, doc := range releaseutil.SplitManifests(manifest) {
+		var obj map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj == nil {
+			continue
+		}
+		kind, _ := obj["kind"].(string)
+		metadata, _ := obj["metadata"].(map[string]any)
+		name, _ := metadata["name"].(string)
+		collectImages(obj, kind+"/"+name, images)
+	}
+	return images
+}
+
+func collectImages(node any, prefix string, images map[string]string) {
+	switch n := node.(type) {
+	case map[string]any:
+		for key, value := range n {
+			if key == "containers" || key == "initContainers" {
+				containers, _ := value.([]any)
+				for _, container := range containers {
+					c, _ := container.(map[string]any)
+					name, _ := c["name"].(string)
+					image, _ := c["image"].(string)
+					if name != "" && image != "" {
+						images[prefix+"/"+name] = image
+					}
+				}
+				continue
+			}
+			collectImages(value, prefix, images)
+		}
+	case []any:
+		for _, value := range n {
+			collectImages(value, prefix, images)
+		}
+	}
+}
+
+func unionKeys(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,229 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+const renderedManifest = `---
+# Source: mdai-cluster/templates/namespace.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: mdai-settings
+  labels:
+    helm.sh/chart: mdai-cluster-0.0.2
+---
+# Source: mdai-cluster/charts/prometheus/templates/deploy.yaml
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: prometheus
+  labels:
+    helm.sh/chart: prometheus-27.5.1
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init-chown
+          image: busybox:1.37
+      containers:
+        - name: prometheus
+          image: quay.io/prometheus/prometheus:v3.2.1
+        - name: reloader
+          image: quay.io/prometheus-operator/prometheus-config-reloader:v0.81.0
+---
+# Source: mdai-cluster/charts/mdai-operator/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: mdai-operator
+  labels:
+    helm.sh/chart: mdai-operator-0.1.5-rc1
+spec:
+  template:
+    spec:
+      containers:
+        - name: manager
+          image: public.ecr.aws/decisiveai/mdai-operator:0.1.5-rc1
+---
+# Source: mdai-cluster/charts/mdai-operator/templates/cronjob.yaml
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: cleanup
+              image: bitnami/kubectl:1.32
+`
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{name: "equal", a: "0.0.2", b: "0.0.2", want: 0},
+		{name: "v prefix", a: "v0.0.2", b: "0.0.2", want: 0},
+		{name: "older", a: "0.0.2", b: "0.0.10", want: -1},
+		{name: "newer", a: "0.1.0", b: "0.0.10", want: 1},
+		{name: "release candidate before release", a: "0.1.5-rc1", b: "0.1.5", want: -1},
+		{name: "release after release candidate", a: "0.1.5", b: "0.1.5-rc1", want: 1},
+		{name: "numbered release candidates", a: "0.1.5-rc.2", b: "0.1.5-rc.10", want: -1},
+		{name: "release candidates compare lexically", a: "0.1.5-rc2", b: "0.1.5-rc10", want: 1},
+		{name: "same invalid", a: "latest", b: "latest", want: 0},
+		{name: "different invalid", a: "latest", b: "0.0.2", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, compareVersions(tt.a, tt.b))
+		})
+	}
+}
+
+func TestManifestCharts(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		umbrella string
+		want     map[string]string
+	}{
+		{
+			name:     "subcharts",
+			manifest: renderedManifest,
+			umbrella: "mdai-cluster",
+			want:     map[string]string{"prometheus": "27.5.1", "mdai-operator": "0.1.5-rc1"},
+		},
+		{
+			name:     "other umbrella",
+			manifest: renderedManifest,
+			umbrella: "opentelemetry-demo",
+			want:     map[string]string{"mdai-cluster": "0.0.2", "prometheus": "27.5.1", "mdai-operator": "0.1.5-rc1"},
+		},
+		{
+			name:     "empty",
+			umbrella: "mdai-cluster",
+			want:     map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, manifestCharts(tt.manifest, tt.umbrella))
+		})
+	}
+}
+
+func TestLockedCharts(t *testing.T) {
+	packaged := func() *chart.Chart {
+		ch := &chart.Chart{Metadata: &chart.Metadata{Name: "mdai-cluster", Version: "0.0.2"}}
+		ch.AddDependency(
+			&chart.Chart{Metadata: &chart.Metadata{Name: "prometheus", Version: "27.5.0"}},
+			&chart.Chart{Metadata: &chart.Metadata{Name: "valkey", Version: "2.4.1"}},
+		)
+		return ch
+	}
+	locked := packaged()
+	locked.Lock = &chart.Lock{Dependencies: []*chart.Dependency{
+		{Name: "prometheus", Version: "27.5.1"},
+		{Name: "mdai-operator", Version: "0.1.5-rc1"},
+	}}
+
+	tests := []struct {
+		name  string
+		chart *chart.Chart
+		want  map[string]string
+	}{
+		{name: "chart lock", chart: locked, want: map[string]string{"prometheus": "27.5.1", "mdai-operator": "0.1.5-rc1"}},
+		{name: "without chart lock", chart: packaged(), want: map[string]string{"prometheus": "27.5.0", "valkey": "2.4.1"}},
+		{name: "without dependencies", chart: &chart.Chart{Metadata: &chart.Metadata{Name: "mdai-cluster"}}, want: map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, lockedCharts(tt.chart))
+		})
+	}
+}
+
+func TestWantedCharts(t *testing.T) {
+	locked := map[string]string{"prometheus": "27.5.1", "mdai-operator": "0.1.5", "valkey": "2.4.1"}
+	tests := []struct {
+		name    string
+		enabled map[string]string
+		want    map[string]string
+	}{
+		{
+			name:    "disabled subchart",
+			enabled: manifestCharts(renderedManifest, "mdai-cluster"),
+			want:    map[string]string{"prometheus": "27.5.1", "mdai-operator": "0.1.5"},
+		},
+		{
+			name:    "all disabled",
+			enabled: map[string]string{},
+			want:    map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, wantedCharts(locked, tt.enabled))
+		})
+	}
+}
+
+func TestManifestImages(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     map[string]string
+	}{
+		{
+			name:     "containers, init containers and job templates",
+			manifest: renderedManifest,
+			want: map[string]string{
+				"StatefulSet/prometheus/init-chown": "busybox:1.37",
+				"StatefulSet/prometheus/prometheus": "quay.io/prometheus/prometheus:v3.2.1",
+				"StatefulSet/prometheus/reloader":   "quay.io/prometheus-operator/prometheus-config-reloader:v0.81.0",
+				"Deployment/mdai-operator/manager":  "public.ecr.aws/decisiveai/mdai-operator:0.1.5-rc1",
+				"CronJob/cleanup/cleanup":           "bitnami/kubectl:1.32",
+			},
+		},
+		{
+			name:     "invalid document",
+			manifest: "---\nkind: [\n---\napiVersion: v1\nkind: Pod\nmetadata:\n  name: debug\nspec:\n  containers:\n    - name: shell\n      image: alpine\n    - name: unnamed\n",
+			want:     map[string]string{"Pod/debug/shell": "alpine"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, manifestImages(tt.manifest))
+		})
+	}
+}
+
+func TestParseChartLabel(t *testing.T) {
+	tests := []struct {
+		label   string
+		name    string
+		version string
+	}{
+		{label: "mdai-cluster-0.0.2", name: "mdai-cluster", version: "0.0.2"},
+		{label: "mdai-cluster-0.0.2-rc1", name: "mdai-cluster", version: "0.0.2-rc1"},
+		{label: "k8s-1-monitoring-1.0.0", name: "k8s", version: "1-monitoring-1.0.0"},
+		{label: "opentelemetry-demo", name: "opentelemetry-demo"},
+		{label: "prometheus-", name: "prometheus-"},
+		{label: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			name, version := ParseChartLabel(tt.label)
+			require.Equal(t, tt.name, name)
+			require.Equal(t, tt.version, version)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+type ChartSource interface {
+	Locate(name, version string) (string, error)
+	String() string
+}
+
+type urlSource struct {
+	template string
+	settings *cli.EnvSettings
+}
+
+type repoSource struct {
+	url      string
+	settings *cli.EnvSettings
+}
+
+type ociSource struct {
+	registry string
+	settings *cli.EnvSettings
+}
+
+type localSource struct {
+	path string
+}
+
+func NewChartSource(ref string, settings *cli.EnvSettings) (ChartSource, error) {
+	ref = strings.TrimSpace(ref)
+	switch {
+	case ref == "":
+		return nil, errors.New("empty chart source")
+	case registry.IsOCI(ref):
+		return &ociSource{registry: strings.TrimSuffix(ref, "/"), settings: settings}, nil
+	case strings.HasPrefix(ref, "http://"), strings.HasPrefix(ref, "https://"):
+		if strings.Contains(ref, "%") {
+			return &urlSource{template: ref, settings: settings}, nil
+		}
+		return &repoSource{url: strings.TrimSuffix(ref, "/"), settings: settings}, nil
+	default:
+		return &localSource{path: strings.TrimPrefix(ref, "file://")}, nil
+	}
+}
+
+func (s *urlSource) Locate(name, version string) (string, error) {
+	return download(fmt.Sprintf(s.template, name, version), "", s.settings, nil)
+}
+
+func (s *urlSource) String() string {
+	return s.template
+}
+
+func (s *repoSource) Locate(name, version string) (string, error) {
+	chartURL, err := repo.FindChartInRepoURL(s.url, name, version, "", "", "", getter.All(s.settings))
+	if err != nil {
+		return "", fmt.Errorf("failed to find chart in repository: %w", err)
+	}
+	return download(chartURL, "", s.settings, nil)
+}
+
+func (s *repoSource) String() string {
+	return s.url
+}
+
+func (s *ociSource) Locate(name, version string) (string, error) {
+	registryClient, err := registry.NewClient(
+		registry.ClientOptCredentialsFile(s.settings.RegistryConfig),
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptWriter(io.Discard),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create registry client: %w", err)
+	}
+	return download(s.registry+"/"+name, version, s.settings, registryClient)
+}
+
+func (s *ociSource) String() string {
+	return s.registry
+}
+
+func (s *localSource) Locate(name, version string) (string, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read chart source: %w", err)
+	}
+	if !info.IsDir() {
+		return filepath.Abs(s.path)
+	}
+	if _, err := os.Stat(filepath.Join(s.path, "Chart.yaml")); err == nil {
+		return filepath.Abs(s.path)
+	}
+	for _, candidate := range []string{
+		filepath.Join(s.path, name+"-"+version+".tgz"),
+		filepath.Join(s.path, name),
+	} {
+		if _, err := os.Stat(candidate); err == nil {
+			return filepath.Abs(candidate)
+		}
+	}
+	return "", fmt.Errorf("chart %s-%s not found in %s", name, version, s.path)
+}
+
+func (s *localSource) String() string {
+	return s.path
+}
+
+// download uses a fresh directory so a stale .prov is never picked up.
+func download(ref, version string, settings *cli.EnvSettings, registryClient *registry.Client) (string, error) {
+	dest, err := os.MkdirTemp("", "mdai-chart-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create download directory: %w", err)
+	}
+	dl := downloader.ChartDownloader{
+		Out:              io.Discard,
+		Verify:           downloader.VerifyLater,
+		Getters:          getter.All(settings),
+		RepositoryConfig: settings.RepositoryConfig,
+		RepositoryCache:  settings.RepositoryCache,
+		RegistryClient:   registryClient,
+	}
+	if registryClient != nil {
+		dl.Options = append(dl.Options, getter.WithRegistryClient(registryClient))
+	}
+	chartPath, _, err := dl.DownloadTo(ref, version, dest)
+	if err != nil {
+		_ = os.RemoveAll(dest)
+		return "", fmt.Errorf("failed to download chart: %w", err)
+	}
+	return chartPath, nil
+}
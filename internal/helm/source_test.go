@@ -0,0 +1,81 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+func TestNewChartSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		ref    string
+		source ChartSource
+		err    string
+	}{
+		{name: "oci", ref: "oci://registry.example.com/charts/", source: &ociSource{registry: "oci://registry.example.com/charts"}},
+		{name: "repo", ref: "https://charts.example.com/", source: &repoSource{url: "https://charts.example.com"}},
+		{name: "url", ref: "https://charts.example.com/%s-%s.tgz", source: &urlSource{template: "https://charts.example.com/%s-%s.tgz"}},
+		{name: "local", ref: " ./charts ", source: &localSource{path: "./charts"}},
+		{name: "local file url", ref: "file:///opt/charts", source: &localSource{path: "/opt/charts"}},
+		{name: "empty", ref: " ", err: "empty chart source"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := NewChartSource(tt.ref, nil)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.source, source)
+		})
+	}
+
+	settings := cli.New()
+	source, err := NewChartSource("oci://registry.example.com/charts", settings)
+	require.NoError(t, err)
+	require.Same(t, settings, source.(*ociSource).settings)
+}
+
+func TestLocalSourceLocate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path string) string {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte("chart"), 0o600))
+		return path
+	}
+	archive := write("archives/mdai-cluster-0.0.2.tgz")
+	write("unpacked/mdai-cluster/Chart.yaml")
+	write("chart/Chart.yaml")
+
+	tests := []struct {
+		name    string
+		path    string
+		version string
+		want    string
+		err     string
+	}{
+		{name: "archive", path: archive, version: "0.0.2", want: archive},
+		{name: "archive in directory", path: filepath.Join(dir, "archives"), version: "0.0.2", want: archive},
+		{name: "chart directory in directory", path: filepath.Join(dir, "unpacked"), version: "0.0.2", want: filepath.Join(dir, "unpacked", "mdai-cluster")},
+		{name: "chart directory", path: filepath.Join(dir, "chart"), version: "0.0.2", want: filepath.Join(dir, "chart")},
+		{name: "other version", path: filepath.Join(dir, "archives"), version: "0.0.3", err: "chart mdai-cluster-0.0.3 not found in " + filepath.Join(dir, "archives")},
+		{name: "missing", path: filepath.Join(dir, "missing"), version: "0.0.2", err: "failed to read chart source"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := (&localSource{path: tt.path}).Locate("mdai-cluster", tt.version)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, path)
+		})
+	}
+}
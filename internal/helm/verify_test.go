@@ -0,0 +1,135 @@
+package helm
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decisiveai/mdai-cli/internal/cache"
+	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyChart(t *testing.T) {
+	dir := t.TempDir()
+	chartPath := filepath.Join(dir, "mdai-cluster-0.0.2.tgz")
+	require.NoError(t, os.WriteFile(chartPath, []byte("chart"), 0o600))
+	digest, err := cache.Digest(chartPath)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		path   string
+		digest string
+		err    bool
+	}{
+		{name: "pinned digest", path: chartPath, digest: digest},
+		{name: "other digest", path: chartPath, digest: "sha256:00", err: true},
+		{name: "no digest or provenance", path: chartPath, err: true},
+		{name: "unpacked chart", path: dir, digest: digest, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyChart(tt.path, &mdaitypes.ChartSpec{ReleaseName: "mdai-cluster", Version: "0.0.2", Digest: tt.digest})
+			if !tt.err {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrUnverified)
+		})
+	}
+}
+
+func TestChartSpecs(t *testing.T) {
+	for name := range chartSpecs {
+		spec, err := getChartSpec(name, "")
+		require.NoError(t, err, name)
+		require.NotEmpty(t, spec.Namespace, name)
+	}
+}
+
+func TestVerifyShippedCharts(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	digests := make(map[string]string)
+	charts := make(map[string]string)
+	for name, spec := range chartSpecs {
+		chartPath := filepath.Join(dir, name+"-"+spec.Version+".tgz")
+		require.NoError(t, os.WriteFile(chartPath, []byte(name), 0o600))
+		digests[name+"-"+spec.Version], err = cache.Digest(chartPath)
+		require.NoError(t, err)
+		charts[name] = chartPath
+	}
+	manifest, err := json.Marshal(digests)
+	require.NoError(t, err)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(private, manifest))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/charts.json":
+			_, _ = w.Write(manifest)
+		case "/charts.json.sig":
+			_, _ = w.Write([]byte(signature))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	oldURL, oldKeys := chartManifestURL, releaseKeys
+	defer func() { chartManifestURL, releaseKeys = oldURL, oldKeys }()
+	chartManifestURL = server.URL + "/charts.json"
+
+	tests := []struct {
+		name     string
+		keys     ed25519.PublicKey
+		tampered bool
+		err      bool
+	}{
+		{name: "signed digests", keys: public},
+		{name: "tampered chart", keys: public, tampered: true, err: true},
+		{name: "untrusted key", keys: make(ed25519.PublicKey, ed25519.PublicKeySize), err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releaseKeys = []byte(base64.StdEncoding.EncodeToString(tt.keys))
+			client := NewClient(WithContext(context.Background()))
+			for name, chartPath := range charts {
+				if tt.tampered {
+					chartPath = filepath.Join(t.TempDir(), filepath.Base(chartPath))
+					require.NoError(t, os.WriteFile(chartPath, []byte("tampered"), 0o600))
+				}
+				spec, err := getChartSpec(name, "")
+				require.NoError(t, err)
+				err = client.verifyChart(chartPath, spec)
+				if tt.err {
+					require.ErrorIs(t, err, ErrUnverified, name)
+					continue
+				}
+				require.NoError(t, err, name)
+			}
+		})
+	}
+}
+
+func TestLocateLocalChart(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "mdai-cluster"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mdai-cluster", "Chart.yaml"), []byte("name: mdai-cluster\n"), 0o600))
+
+	client := NewClient(WithContext(context.Background()))
+	client.chartRepo = dir
+	spec, err := getChartSpec("mdai-cluster", "")
+	require.NoError(t, err)
+	chartPath, cleanup, err := client.locateChart(spec, client.envSettings, true)
+	require.NoError(t, err)
+	defer cleanup()
+	require.Equal(t, filepath.Join(dir, "mdai-cluster"), chartPath)
+}
@@ -3,20 +3,28 @@ package kubehelper
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/decisiveai/mdai-cli/internal/config"
 	mdaitypes "github.com/decisiveai/mdai-cli/internal/types"
 	mydecisivev1 "github.com/decisiveai/mydecisive-engine-operator/api/v1"
 	opentelemetry "github.com/decisiveai/opentelemetry-operator/apis/v1alpha1"
+	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -29,26 +37,21 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
-const (
-	namespace    = "mdai"
-	operatorName = "mydecisiveengine-sample-1"
-)
-
-var mdaiOperator = mydecisivev1.MyDecisiveEngine{
-	ObjectMeta: metav1.ObjectMeta{
-		Name:      operatorName,
-		Namespace: namespace,
-	},
-}
+const FieldManager = "mdai-cli"
 
 type Helper struct {
 	kubeconfig             string
 	kubecontext            string
+	namespace              string
+	engine                 string
+	collector              string
 	restConfig             *rest.Config
 	apiConfig              *api.Config
 	apiExtensionsClientset *apiextensionsclient.Clientset
 	k8sClient              client.Client
 	clientset              *kubernetes.Clientset
+	timeout                time.Duration
+	backoff                wait.Backoff
 }
 
 type HelperOption func(*Helper)
@@ -61,11 +64,29 @@ func WithContext(ctx context.Context) HelperOption {
 		if kubecontext, ok := ctx.Value(mdaitypes.Kubecontext{}).(string); ok {
 			helper.kubecontext = kubecontext
 		}
+		if namespace, ok := ctx.Value(mdaitypes.Namespace{}).(string); ok && namespace != "" {
+			helper.namespace = namespace
+		}
+		if engine, ok := ctx.Value(mdaitypes.Engine{}).(string); ok {
+			helper.engine = engine
+		}
+		if collector, ok := ctx.Value(mdaitypes.Collector{}).(string); ok {
+			helper.collector = collector
+		}
+		if timeout, ok := ctx.Value(mdaitypes.Timeout{}).(time.Duration); ok {
+			helper.timeout = timeout
+		}
+		if retries, ok := ctx.Value(mdaitypes.Retries{}).(int); ok {
+			helper.backoff = Backoff(retries)
+		}
 	}
 }
 
 func New(options ...HelperOption) (*Helper, error) {
 	helper := new(Helper)
+	helper.namespace = config.DefaultNamespace
+	helper.timeout = DefaultTimeout
+	helper.backoff = Backoff(DefaultRetries)
 	for _, option := range options {
 		option(helper)
 	}
@@ -115,31 +136,121 @@ func New(options ...HelperOption) (*Helper, error) {
 }
 
 func (helper *Helper) GetOperator(ctx context.Context) (*mydecisivev1.MyDecisiveEngine, error) {
+	if helper.engine != "" {
+		get := mydecisivev1.MyDecisiveEngine{}
+		if err := helper.k8sClient.Get(ctx, client.ObjectKey{Namespace: helper.namespace, Name: helper.engine}, &get); err != nil {
+			return nil, fmt.Errorf("failed to get mydecisivev1.MyDecisiveEngine %s: %w", helper.engine, err)
+		}
+		return &get, nil
+	}
+
 	list := mydecisivev1.MyDecisiveEngineList{}
 	if err := helper.k8sClient.List(
 		ctx,
 		&list,
 		&client.ListOptions{
-			Namespace: namespace,
+			Namespace: helper.namespace,
 		},
 	); err != nil {
 		return nil, fmt.Errorf("failed to get operator list: %w", err)
 	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("no mydecisivev1.MyDecisiveEngine found in namespace %s", helper.namespace)
+	}
 	if len(list.Items) > 1 {
 		operatorNames := make([]string, len(list.Items))
 		for i, item := range list.Items {
 			operatorNames[i] = item.GetName()
 		}
-		return nil, fmt.Errorf("more than one mydecisivev1.MyDecisiveEngine found [%s]", strings.Join(operatorNames, ", "))
+		return nil, fmt.Errorf("more than one mydecisivev1.MyDecisiveEngine found [%s], select one with --engine", strings.Join(operatorNames, ", "))
 	}
 
 	return &list.Items[0], nil
 }
 
+func (helper *Helper) ListOperators(ctx context.Context) ([]mydecisivev1.MyDecisiveEngine, error) {
+	list := mydecisivev1.MyDecisiveEngineList{}
+	if err := helper.k8sClient.List(ctx, &list, client.InNamespace(helper.namespace)); err != nil {
+		return nil, fmt.Errorf("failed to get operator list: %w", err)
+	}
+	return list.Items, nil
+}
+
+func (helper *Helper) DeleteOperator(ctx context.Context, operator *mydecisivev1.MyDecisiveEngine) error {
+	if err := helper.k8sClient.Delete(ctx, operator); err != nil {
+		return fmt.Errorf("failed to delete mydecisivev1.MyDecisiveEngine %s: %w", operator.Name, err)
+	}
+	return nil
+}
+
+// GetCollector falls back to the first collector when none is selected.
+func (helper *Helper) GetCollector(ctx context.Context) (int, *mydecisivev1.Collector, error) {
+	operator, err := helper.GetOperator(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	collectors := operator.Spec.TelemetryModule.Collectors
+	if helper.collector == "" {
+		if len(collectors) == 0 {
+			return 0, nil, fmt.Errorf("mydecisivev1.MyDecisiveEngine %s has no collectors", operator.Name)
+		}
+		return 0, &collectors[0], nil
+	}
+	for i := range collectors {
+		if collectors[i].Name == helper.collector {
+			return i, &collectors[i], nil
+		}
+	}
+	return 0, nil, fmt.Errorf(`collector "%s" not found in mydecisivev1.MyDecisiveEngine %s`, helper.collector, operator.Name)
+}
+
+func (helper *Helper) Namespace() string {
+	return helper.namespace
+}
+
+func (helper *Helper) GetOperatorManifest(ctx context.Context) ([]byte, error) {
+	list := unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(mydecisivev1.GroupVersion.WithKind("MyDecisiveEngineList"))
+	if err := helper.k8sClient.List(ctx, &list, client.InNamespace(helper.namespace)); err != nil {
+		return nil, fmt.Errorf("failed to get operator list: %w", err)
+	}
+	if helper.engine != "" {
+		list.Items = slices.DeleteFunc(list.Items, func(item unstructured.Unstructured) bool {
+			return item.GetName() != helper.engine
+		})
+	}
+	switch len(list.Items) {
+	case 0:
+		return nil, fmt.Errorf("no mydecisivev1.MyDecisiveEngine found in namespace %s", helper.namespace)
+	case 1:
+	default:
+		operatorNames := make([]string, len(list.Items))
+		for i, item := range list.Items {
+			operatorNames[i] = item.GetName()
+		}
+		return nil, fmt.Errorf("more than one mydecisivev1.MyDecisiveEngine found [%s], select one with --engine", strings.Join(operatorNames, ", "))
+	}
+
+	obj := list.Items[0]
+	for _, field := range [][]string{
+		{"status"},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "generation"},
+		{"metadata", "managedFields"},
+		{"metadata", "resourceVersion"},
+		{"metadata", "selfLink"},
+		{"metadata", "uid"},
+		{"metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration"},
+	} {
+		unstructured.RemoveNestedField(obj.Object, field...)
+	}
+	return yaml.Marshal(obj.Object)
+}
+
 func (helper *Helper) GetOTELOperator(ctx context.Context) (*opentelemetry.OpenTelemetryCollector, error) {
 	get := opentelemetry.OpenTelemetryCollector{}
 	if err := helper.k8sClient.Get(ctx, client.ObjectKey{
-		Namespace: namespace,
+		Namespace: helper.namespace,
 		Name:      "gateway",
 	}, &get); err != nil {
 		return nil, fmt.Errorf("failed to get opentelemetry operator: %w", err)
@@ -148,18 +259,25 @@ func (helper *Helper) GetOTELOperator(ctx context.Context) (*opentelemetry.OpenT
 }
 
 func (helper *Helper) GetTelemetryFiltering(ctx context.Context) (*mydecisivev1.TelemetryFilterConfig, error) {
-	operator, err := helper.GetOperator(ctx)
+	_, collector, err := helper.GetCollector(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mdai operator: %w", err)
 	}
-	return operator.Spec.TelemetryModule.Collectors[0].TelemetryFiltering, nil
+	return collector.TelemetryFiltering, nil
 }
 
 func (helper *Helper) Patch(ctx context.Context, patchType types.PatchType, patch []byte) error {
+	operator, err := helper.GetOperator(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to patch mdai operator: %w", err)
+	}
+	if err := helper.validatePatch(ctx, operator.Name, patchType, patch); err != nil {
+		return err
+	}
 	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		err := helper.k8sClient.Patch(
 			ctx,
-			&mdaiOperator,
+			operator,
 			client.RawPatch(patchType, patch),
 		)
 		return err
@@ -174,6 +292,70 @@ func (helper *Helper) Apply(ctx context.Context, manifest []byte, gvk *schema.Gr
 	if err != nil {
 		return fmt.Errorf("failed to get object: %w", err)
 	}
+	if isEngine(obj) {
+		if err := helper.validateEngine(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return retry.OnError(helper.backoff, IsTransient, func() error {
+		return helper.apply(ctx, manifest, gvk, false)
+	})
+}
+
+func (helper *Helper) WaitForCRD(ctx context.Context, crd string) error {
+	var lastErr error
+	if err := wait.PollUntilContextTimeout(ctx, pollInterval, helper.timeout, true, func(ctx context.Context) (bool, error) {
+		get, err := helper.GetCRD(ctx, crd)
+		if err != nil {
+			lastErr = err
+			return false, nil
+		}
+		for _, condition := range get.Status.Conditions {
+			if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+				return true, nil
+			}
+		}
+		lastErr = fmt.Errorf("crd %s is not established", crd)
+		return false, nil
+	}); err != nil {
+		return fmt.Errorf("timed out waiting for crd %s: %w", crd, errors.Join(err, lastErr))
+	}
+	return nil
+}
+
+func (helper *Helper) WaitForAdmission(ctx context.Context, manifest []byte, gvk *schema.GroupVersionKind) error {
+	var lastErr error
+	if err := wait.PollUntilContextTimeout(ctx, pollInterval, helper.timeout, true, func(ctx context.Context) (bool, error) {
+		lastErr = helper.apply(ctx, manifest, gvk, true)
+		switch {
+		case lastErr == nil:
+			return true, nil
+		case IsTransient(lastErr):
+			return false, nil
+		default:
+			return false, lastErr
+		}
+	}); err != nil {
+		return fmt.Errorf("timed out waiting for admission webhooks: %w", errors.Join(err, lastErr))
+	}
+	return nil
+}
+
+func (helper *Helper) apply(ctx context.Context, manifest []byte, gvk *schema.GroupVersionKind, dryRun bool) error {
+	obj, err := getObject(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to get object: %w", err)
+	}
+	obj.SetNamespace(helper.namespace)
+
+	var (
+		createOpts []client.CreateOption
+		updateOpts []client.UpdateOption
+	)
+	if dryRun {
+		createOpts = append(createOpts, client.DryRunAll)
+		updateOpts = append(updateOpts, client.DryRunAll)
+	}
 
 	get := unstructured.Unstructured{}
 	get.SetGroupVersionKind(*gvk)
@@ -184,13 +366,13 @@ func (helper *Helper) Apply(ctx context.Context, manifest []byte, gvk *schema.Gr
 		if !k8serrors.IsNotFound(err) {
 			return fmt.Errorf("failed to get manifest: %w", err)
 		}
-		if err := helper.k8sClient.Create(ctx, obj); err != nil {
+		if err := helper.k8sClient.Create(ctx, obj, createOpts...); err != nil {
 			return fmt.Errorf("failed to create manifest: %w", err)
 		}
 		return nil
 	}
 	obj.SetResourceVersion(get.GetResourceVersion())
-	if err := helper.k8sClient.Update(ctx, obj); err != nil {
+	if err := helper.k8sClient.Update(ctx, obj, updateOpts...); err != nil {
 		return fmt.Errorf("failed to update manifest: %w", err)
 	}
 
@@ -212,10 +394,84 @@ func (helper *Helper) GetDeployment(ctx context.Context, deployment, namespace s
 	return helper.clientset.AppsV1().Deployments(namespace).Get(ctx, deployment, metav1.GetOptions{})
 }
 
+func (helper *Helper) ListDeployments(ctx context.Context, namespace string) (*appsv1.DeploymentList, error) {
+	return helper.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+}
+
+func (helper *Helper) ListStatefulSets(ctx context.Context, namespace string) (*appsv1.StatefulSetList, error) {
+	return helper.clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+}
+
+func (helper *Helper) ListDaemonSets(ctx context.Context, namespace string) (*appsv1.DaemonSetList, error) {
+	return helper.clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+}
+
+func (helper *Helper) ListPods(ctx context.Context, namespace string) (*corev1.PodList, error) {
+	return helper.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+}
+
+func (helper *Helper) GetCRD(ctx context.Context, crd string) (*apiextensionsv1.CustomResourceDefinition, error) {
+	return helper.apiExtensionsClientset.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crd, metav1.GetOptions{})
+}
+
+func (helper *Helper) ListCustomResources(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) ([]unstructured.Unstructured, error) {
+	var version string
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			version = v.Name
+		}
+	}
+	listKind := crd.Spec.Names.ListKind
+	if listKind == "" {
+		listKind = crd.Spec.Names.Kind + "List"
+	}
+
+	list := unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{Group: crd.Spec.Group, Version: version, Kind: listKind})
+	if err := helper.k8sClient.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", crd.Name, err)
+	}
+	return list.Items, nil
+}
+
+func (helper *Helper) WaitForCRDDeletion(ctx context.Context, crd string) error {
+	if err := wait.PollUntilContextTimeout(ctx, pollInterval, helper.timeout, true, func(ctx context.Context) (bool, error) {
+		_, err := helper.GetCRD(ctx, crd)
+		return k8serrors.IsNotFound(err), nil
+	}); err != nil {
+		return fmt.Errorf("timed out waiting for crd %s to be deleted: %w", crd, err)
+	}
+	return nil
+}
+
+func (helper *Helper) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	return helper.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+}
+
 func (helper *Helper) GetPodByLabel(ctx context.Context, namespace, labelSelector string) (*corev1.PodList, error) {
 	return helper.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
 }
 
+func (helper *Helper) CreateJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error) {
+	return helper.clientset.BatchV1().Jobs(job.Namespace).Create(ctx, job, metav1.CreateOptions{})
+}
+
+func (helper *Helper) ListJobs(ctx context.Context, namespace, labelSelector string) (*batchv1.JobList, error) {
+	return helper.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+}
+
+func (helper *Helper) DeleteJobs(ctx context.Context, namespace, labelSelector string) error {
+	propagation := metav1.DeletePropagationBackground
+	if err := helper.clientset.BatchV1().Jobs(namespace).DeleteCollection(
+		ctx,
+		metav1.DeleteOptions{PropagationPolicy: &propagation},
+		metav1.ListOptions{LabelSelector: labelSelector},
+	); err != nil {
+		return fmt.Errorf("failed to delete jobs: %w", err)
+	}
+	return nil
+}
+
 func getObject(manifest []byte) (*unstructured.Unstructured, error) {
 	var decodedObj map[string]interface{}
 	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 1024) //nolint: mnd
@@ -224,3 +480,50 @@ func getObject(manifest []byte) (*unstructured.Unstructured, error) {
 	}
 	return &unstructured.Unstructured{Object: decodedObj}, nil
 }
+
+// ApplySecret creates the secret in the helper namespace, or replaces the
+// data of an existing one.
+func (helper *Helper) ApplySecret(ctx context.Context, secret *corev1.Secret) error {
+	secrets := helper.clientset.CoreV1().Secrets(helper.namespace)
+	existing, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		secret.Namespace = helper.namespace
+		if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create secret %s: %w", secret.Name, err)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get secret %s: %w", secret.Name, err)
+	}
+	existing.Data = secret.Data
+	if existing.Labels == nil {
+		existing.Labels = make(map[string]string)
+	}
+	for k, v := range secret.Labels {
+		existing.Labels[k] = v
+	}
+	if _, err := secrets.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update secret %s: %w", secret.Name, err)
+	}
+	return nil
+}
+
+func (helper *Helper) ServerSideApply(ctx context.Context, manifest []byte) error {
+	obj, err := getObject(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to get object: %w", err)
+	}
+	if isEngine(obj) {
+		if err := helper.validateEngine(ctx, obj); err != nil {
+			return err
+		}
+	}
+	obj.SetNamespace(helper.namespace)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+	if err := helper.k8sClient.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to apply %s: %w", obj.GetName(), err)
+	}
+	return nil
+}
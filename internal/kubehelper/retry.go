@@ -0,0 +1,54 @@
+package kubehelper
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	DefaultTimeout = 2 * time.Minute
+	DefaultRetries = 5
+	pollInterval   = 2 * time.Second
+)
+
+func Backoff(retries int) wait.Backoff {
+	return wait.Backoff{
+		Steps:    max(retries, 1),
+		Duration: time.Second,
+		Factor:   2, //nolint: mnd
+		Jitter:   0.1,
+		Cap:      30 * time.Second, //nolint: mnd
+	}
+}
+
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	switch {
+	case k8serrors.IsServerTimeout(err),
+		k8serrors.IsTimeout(err),
+		k8serrors.IsTooManyRequests(err),
+		k8serrors.IsServiceUnavailable(err),
+		k8serrors.IsInternalError(err),
+		k8serrors.IsUnexpectedServerError(err),
+		meta.IsNoMatchError(err),
+		utilnet.IsConnectionRefused(err),
+		utilnet.IsConnectionReset(err),
+		utilnet.IsProbableEOF(err):
+		return true
+	case errors.As(err, &netErr):
+		return netErr.Timeout()
+	}
+	// Last resort: helm formats hook errors with %s, so an admission webhook
+	// whose service is not ready yet only survives as text.
+	return strings.Contains(err.Error(), "failed calling webhook")
+}
@@ -0,0 +1,148 @@
+package kubehelper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	mydecisivev1 "github.com/decisiveai/mydecisive-engine-operator/api/v1"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EngineCRD is the crd every engine write is validated against.
+const EngineCRD = "mydecisiveengines.mydecisive.ai"
+
+var typeMismatch = regexp.MustCompile(`must be of type (\w+): "(\w+)"$`)
+
+// ValidateCustomResource checks obj against the OpenAPI schema of its version
+// of the crd on the cluster, reporting fields the schema does not know too.
+// Users without access to the cluster scoped crd leave it to the api server.
+func (helper *Helper) ValidateCustomResource(ctx context.Context, crd string, obj map[string]any) error {
+	get, err := helper.GetCRD(ctx, crd)
+	switch {
+	case k8serrors.IsForbidden(err) || k8serrors.IsNotFound(err):
+		log.FromContext(ctx).Debugf("skipping validation against crd %s: %v", crd, err)
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get crd %s: %w", crd, err)
+	}
+	apiVersion, _ := obj["apiVersion"].(string)
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return fmt.Errorf("invalid apiVersion %s: %w", apiVersion, err)
+	}
+	var version *apiextensionsv1.CustomResourceDefinitionVersion
+	for i := range get.Spec.Versions {
+		if get.Spec.Versions[i].Name == gv.Version {
+			version = &get.Spec.Versions[i]
+		}
+	}
+	switch {
+	case gv.Group != get.Spec.Group || version == nil:
+		return fmt.Errorf("apiVersion %s is not served by crd %s", apiVersion, crd)
+	case version.Schema == nil || version.Schema.OpenAPIV3Schema == nil:
+		return nil
+	}
+
+	internal := new(apiextensions.JSONSchemaProps)
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(version.Schema.OpenAPIV3Schema, internal, nil); err != nil {
+		return fmt.Errorf("failed to convert crd %s schema: %w", crd, err)
+	}
+	validator, _, err := apiservervalidation.NewSchemaValidator(internal)
+	if err != nil {
+		return fmt.Errorf("failed to load crd %s schema: %w", crd, err)
+	}
+	var errs []error
+	for _, err := range apiservervalidation.ValidateCustomResource(nil, obj, validator) {
+		errs = append(errs, fmt.Errorf("%s: %s", err.Field, explain(err)))
+	}
+	if structural, err := structuralschema.NewStructural(internal); err == nil {
+		for _, path := range pruning.PruneWithOptions(runtime.DeepCopyJSON(obj), structural, true, structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true}) {
+			errs = append(errs, fmt.Errorf("%s: unknown field", path))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// explain drops the value and path the api server repeats in the detail.
+func explain(err *field.Error) string {
+	detail := strings.TrimPrefix(err.Detail, err.Field+" in body ")
+	switch err.Type {
+	case field.ErrorTypeRequired:
+		return "required field is missing"
+	case field.ErrorTypeNotSupported:
+		return fmt.Sprintf("%v is not supported, expected one of %s", err.BadValue, strings.TrimPrefix(detail, "supported values: "))
+	case field.ErrorTypeInvalid:
+		if match := typeMismatch.FindStringSubmatch(detail); match != nil {
+			return fmt.Sprintf("expected %s, got %s", match[1], match[2])
+		}
+		return fmt.Sprintf("%s, got %v", detail, err.BadValue)
+	default:
+		return strings.ToLower(err.Type.String()) + ": " + detail
+	}
+}
+
+// isEngine reports whether obj is a mydecisivev1.MyDecisiveEngine.
+func isEngine(obj *unstructured.Unstructured) bool {
+	return obj.GroupVersionKind().GroupKind() == mydecisivev1.GroupVersion.WithKind("MyDecisiveEngine").GroupKind()
+}
+
+func (helper *Helper) validateEngine(ctx context.Context, obj *unstructured.Unstructured) error {
+	if err := helper.ValidateCustomResource(ctx, EngineCRD, obj.Object); err != nil {
+		return fmt.Errorf("invalid mydecisivev1.MyDecisiveEngine %s, not applied:\n%w", obj.GetName(), err)
+	}
+	return nil
+}
+
+// validatePatch applies patch to the engine locally and validates the result.
+func (helper *Helper) validatePatch(ctx context.Context, name string, patchType types.PatchType, patch []byte) error {
+	current := unstructured.Unstructured{}
+	current.SetGroupVersionKind(mydecisivev1.GroupVersion.WithKind("MyDecisiveEngine"))
+	if err := helper.k8sClient.Get(ctx, client.ObjectKey{Namespace: helper.namespace, Name: name}, &current); err != nil {
+		return fmt.Errorf("failed to get mdai operator: %w", err)
+	}
+	original, err := json.Marshal(current.Object)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mdai operator: %w", err)
+	}
+
+	var patched []byte
+	switch patchType {
+	case types.JSONPatchType:
+		decoded, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return fmt.Errorf("invalid patch: %w", err)
+		}
+		if patched, err = decoded.Apply(original); err != nil {
+			return fmt.Errorf("failed to apply patch: %w", err)
+		}
+	case types.MergePatchType:
+		if patched, err = jsonpatch.MergePatch(original, patch); err != nil {
+			return fmt.Errorf("failed to apply patch: %w", err)
+		}
+	default:
+		return nil
+	}
+
+	obj := unstructured.Unstructured{}
+	if err := json.Unmarshal(patched, &obj.Object); err != nil {
+		return fmt.Errorf("failed to unmarshal patched mdai operator: %w", err)
+	}
+	return helper.validateEngine(ctx, &obj)
+}
@@ -0,0 +1,48 @@
+package kubehelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestExplain(t *testing.T) {
+	path := field.NewPath("spec", "telemetryModule", "collectors").Index(0).Child("enabled")
+	tests := []struct {
+		name string
+		err  *field.Error
+		want string
+	}{
+		{
+			name: "required",
+			err:  field.Required(path, ""),
+			want: "required field is missing",
+		},
+		{
+			name: "not supported",
+			err:  field.NotSupported(path, "maybe", []string{"true", "false"}),
+			want: `maybe is not supported, expected one of "true", "false"`,
+		},
+		{
+			name: "type mismatch",
+			err:  field.Invalid(path, "yes", path.String()+` in body must be of type boolean: "string"`),
+			want: "expected boolean, got string",
+		},
+		{
+			name: "invalid",
+			err:  field.Invalid(path, 0, path.String()+" in body should be greater than or equal to 1"),
+			want: "should be greater than or equal to 1, got 0",
+		},
+		{
+			name: "other",
+			err:  field.TooMany(path, 3, 2),
+			want: "too many: must have at most 2 items",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, explain(tt.err))
+		})
+	}
+}
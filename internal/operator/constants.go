@@ -1,12 +1,28 @@
 package operator
 
+import (
+	"fmt"
+
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
+)
+
 const (
 	PatchOpAdd             = "add"
 	PatchOpRemove          = "remove"
 	PatchOpReplace         = "replace"
-	DatalyzerJSONPath      = "/spec/telemetryModule/collectors/0/measureVolumes"
-	MutedPipelinesJSONPath = "/spec/telemetryModule/collectors/0/telemetryFiltering/filters/%v"
-	OtelConfigJSONPath     = "/spec/telemetryModule/collectors/0/spec/config"
+	PatchOpTest            = "test"
+	CollectorsJSONPath     = "/spec/telemetryModule/collectors/%v"
+	CollectorNameJSONPath  = "/spec/telemetryModule/collectors/%d/name"
+	DatalyzerJSONPath      = "/spec/telemetryModule/collectors/%d/measureVolumes"
+	TelemetryFilteringPath = "/spec/telemetryModule/collectors/%d/telemetryFiltering"
+	MutedPipelinesJSONPath = "/spec/telemetryModule/collectors/%d/telemetryFiltering/filters/%v"
+	OtelConfigJSONPath     = "/spec/telemetryModule/collectors/%d/spec/config"
+	CollectorEnvJSONPath   = "/spec/telemetryModule/collectors/%d/spec/env"
+	EngineCRD              = kubehelper.EngineCRD
+	PartOfLabel            = "app.kubernetes.io/part-of"
+	PartOf                 = "mydecisive-engine-operator"
 )
 
-var MutedPipelineEmptyFilter = []byte(`[{ "op": "add", "path": "/spec/telemetryModule/collectors/0/telemetryFiltering", "value": { "filters": [] } }]`)
+func MutedPipelineEmptyFilter(collector int) []byte {
+	return []byte(fmt.Sprintf(`[{ "op": "add", "path": "`+TelemetryFilteringPath+`", "value": { "filters": [] } }]`, collector))
+}
@@ -0,0 +1,144 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
+	mydecisivev1 "github.com/decisiveai/mydecisive-engine-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+func ListEngines(ctx context.Context) ([]mydecisivev1.MyDecisiveEngine, error) {
+	helper, err := kubehelper.New(WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	return helper.ListOperators(ctx)
+}
+
+func DeleteEngine(ctx context.Context, engine *mydecisivev1.MyDecisiveEngine) error {
+	helper, err := kubehelper.New(WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	return helper.DeleteOperator(ctx, engine)
+}
+
+func CreateCollector(ctx context.Context, collector mydecisivev1.Collector) error {
+	helper, err := kubehelper.New(WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	engine, err := helper.GetOperator(ctx)
+	if err != nil {
+		return err
+	}
+	for _, existing := range engine.Spec.TelemetryModule.Collectors {
+		if existing.Name == collector.Name {
+			return fmt.Errorf(`collector "%s" already exists in mydecisivev1.MyDecisiveEngine %s`, collector.Name, engine.Name)
+		}
+	}
+
+	patchBytes, err := json.Marshal(
+		[]collectorPatch{
+			{
+				Op:    PatchOpAdd,
+				Path:  fmt.Sprintf(CollectorsJSONPath, "-"),
+				Value: collector,
+			},
+		})
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %w", err)
+	}
+	if err := helper.Patch(ctx, types.JSONPatchType, patchBytes); err != nil {
+		return fmt.Errorf("failed to create collector: %w", err)
+	}
+	return nil
+}
+
+// DeleteCollector removes the named collector, the test operation makes the
+// patch fail instead of removing another collector if the list changed.
+func DeleteCollector(ctx context.Context, name string) error {
+	helper, err := kubehelper.New(WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	engine, err := helper.GetOperator(ctx)
+	if err != nil {
+		return err
+	}
+	index := -1
+	for i, collector := range engine.Spec.TelemetryModule.Collectors {
+		if collector.Name == name {
+			index = i
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf(`collector "%s" not found in mydecisivev1.MyDecisiveEngine %s`, name, engine.Name)
+	}
+
+	patchBytes, err := json.Marshal(
+		[]namePatch{
+			{
+				Op:    PatchOpTest,
+				Path:  fmt.Sprintf(CollectorNameJSONPath, index),
+				Value: name,
+			},
+			{
+				Op:   PatchOpRemove,
+				Path: fmt.Sprintf(CollectorsJSONPath, index),
+			},
+		})
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %w", err)
+	}
+	if err := helper.Patch(ctx, types.JSONPatchType, patchBytes); err != nil {
+		return fmt.Errorf("failed to delete collector: %w", err)
+	}
+	return nil
+}
+
+// GetEngineManifest returns the selected engine as yaml, without status and
+// server managed metadata.
+func GetEngineManifest(ctx context.Context) ([]byte, error) {
+	helper, err := kubehelper.New(WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	return helper.GetOperatorManifest(ctx)
+}
+
+// ValidateEngine checks an engine manifest against the engine CRD schema on
+// the cluster, and that it still is the engine named name.
+func ValidateEngine(ctx context.Context, manifest []byte, name string) error {
+	helper, err := kubehelper.New(WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	obj := make(map[string]any)
+	if err := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), len(manifest)).Decode(&obj); err != nil {
+		return fmt.Errorf("failed to parse engine: %w", err)
+	}
+	engine := unstructured.Unstructured{Object: obj}
+	switch {
+	case engine.GetKind() != "MyDecisiveEngine":
+		return fmt.Errorf("kind: expected MyDecisiveEngine, got %q", engine.GetKind())
+	case engine.GetName() != name:
+		return fmt.Errorf("metadata.name: cannot be changed from %s to %q", name, engine.GetName())
+	}
+	return helper.ValidateCustomResource(ctx, EngineCRD, obj)
+}
+
+// ApplyEngine applies an engine manifest with server-side apply.
+func ApplyEngine(ctx context.Context, manifest []byte) error {
+	helper, err := kubehelper.New(WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	return helper.ServerSideApply(ctx, manifest)
+}
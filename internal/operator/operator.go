@@ -28,6 +28,15 @@ func GetOperator(ctx context.Context) (*mydecisivev1.MyDecisiveEngine, error) {
 	return helper.GetOperator(ctx)
 }
 
+func GetCollector(ctx context.Context) (*mydecisivev1.Collector, error) {
+	helper, err := kubehelper.New(WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	_, collector, err := helper.GetCollector(ctx)
+	return collector, err
+}
+
 func CreateTelemetryFilter(ctx context.Context, options ...TelemetryFilterOption) error {
 	newTelemetryFilter := new(telemetryFilter)
 	options = append(options, WithEnable())
@@ -40,20 +49,22 @@ func CreateTelemetryFilter(ctx context.Context, options ...TelemetryFilterOption
 		return fmt.Errorf("failed to initialize kubehelper: %w", err)
 	}
 
+	index, collector, err := helper.GetCollector(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get telemetry filtering: %w", err)
+	}
+
 	patch := []mutePatch{
 		{
 			Op:    PatchOpAdd,
-			Path:  fmt.Sprintf(MutedPipelinesJSONPath, "-"),
+			Path:  fmt.Sprintf(MutedPipelinesJSONPath, index, "-"),
 			Value: newTelemetryFilter.filter,
 		},
 	}
 
-	telemetryFiltering, err := helper.GetTelemetryFiltering(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get telemetry filtering: %w", err)
-	}
+	telemetryFiltering := collector.TelemetryFiltering
 	if telemetryFiltering == nil {
-		if err := helper.Patch(ctx, types.JSONPatchType, MutedPipelineEmptyFilter); err != nil {
+		if err := helper.Patch(ctx, types.JSONPatchType, MutedPipelineEmptyFilter(index)); err != nil {
 			return fmt.Errorf("failed to patch telemetry filtering: %w", err)
 		}
 		telemetryFiltering, err = helper.GetTelemetryFiltering(ctx)
@@ -67,7 +78,7 @@ func CreateTelemetryFilter(ctx context.Context, options ...TelemetryFilterOption
 			patch = []mutePatch{
 				{
 					Op:    PatchOpReplace,
-					Path:  fmt.Sprintf(MutedPipelinesJSONPath, i),
+					Path:  fmt.Sprintf(MutedPipelinesJSONPath, index, i),
 					Value: newTelemetryFilter.filter,
 				},
 			}
@@ -107,11 +118,16 @@ func UpdateOTELConfig(ctx context.Context, config string) error {
 		return fmt.Errorf("failed to initialize kubehelper: %w", err)
 	}
 
+	index, _, err := helper.GetCollector(ctx)
+	if err != nil {
+		return err
+	}
+
 	patchBytes, err := json.Marshal(
 		[]otelConfigPatch{
 			{
 				Op:    PatchOpAdd,
-				Path:  OtelConfigJSONPath,
+				Path:  fmt.Sprintf(OtelConfigJSONPath, index),
 				Value: config,
 			},
 		})
@@ -132,6 +148,12 @@ func Install(ctx context.Context, manifest []byte) error {
 		return fmt.Errorf("failed to initialize kubehelper: %w", err)
 	}
 	gvk := mydecisivev1.GroupVersion.WithKind("MyDecisiveEngine")
+	if err := helper.WaitForCRD(ctx, EngineCRD); err != nil {
+		return err
+	}
+	if err := helper.WaitForAdmission(ctx, manifest, &gvk); err != nil {
+		return err
+	}
 	return helper.Apply(ctx,
 		manifest,
 		&gvk,
@@ -144,11 +166,16 @@ func setMeasureVolumes(ctx context.Context, v bool) error {
 		return fmt.Errorf("failed to initialize kubehelper: %w", err)
 	}
 
+	index, _, err := helper.GetCollector(ctx)
+	if err != nil {
+		return err
+	}
+
 	patchBytes, err := json.Marshal(
 		[]datalyzerPatch{
 			{
 				Op:    PatchOpReplace,
-				Path:  DatalyzerJSONPath,
+				Path:  fmt.Sprintf(DatalyzerJSONPath, index),
 				Value: v,
 			},
 		})
@@ -184,10 +211,11 @@ func toggleTelemetryFilter(ctx context.Context, options ...TelemetryFilterOption
 		return fmt.Errorf("failed to initialize api: %w", err)
 	}
 
-	telemetryFiltering, err := helper.GetTelemetryFiltering(ctx)
+	index, collector, err := helper.GetCollector(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get telemetry filtering: %w", err)
 	}
+	telemetryFiltering := collector.TelemetryFiltering
 	if telemetryFiltering == nil {
 		return fmt.Errorf("filter %s not found", tf.filter.Name)
 	}
@@ -203,14 +231,14 @@ func toggleTelemetryFilter(ctx context.Context, options ...TelemetryFilterOption
 			patch = []mutePatch{
 				{
 					Op:   PatchOpRemove,
-					Path: fmt.Sprintf(MutedPipelinesJSONPath, i),
+					Path: fmt.Sprintf(MutedPipelinesJSONPath, index, i),
 				},
 			}
 		} else {
 			patch = []mutePatch{
 				{
 					Op:    PatchOpReplace,
-					Path:  fmt.Sprintf(MutedPipelinesJSONPath, i),
+					Path:  fmt.Sprintf(MutedPipelinesJSONPath, index, i),
 					Value: filter,
 				},
 			}
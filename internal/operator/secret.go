@@ -0,0 +1,67 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SetSecret stores value in the named secret and exposes it to the collector
+// as the env variable env, so its config can reference ${env:ENV}.
+func SetSecret(ctx context.Context, name, env string, value []byte) error {
+	helper, err := kubehelper.New(WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to initialize kubehelper: %w", err)
+	}
+	if err := helper.ApplySecret(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"app.kubernetes.io/managed-by": "mdai-cli"},
+		},
+		Data: map[string][]byte{name: value},
+	}); err != nil {
+		return err
+	}
+
+	index, collector, err := helper.GetCollector(ctx)
+	if err != nil {
+		return err
+	}
+	envVar := corev1.EnvVar{
+		Name: env,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  name,
+			},
+		},
+	}
+	envVars := slices.Clone(collector.Spec.Env)
+	if i := slices.IndexFunc(envVars, func(e corev1.EnvVar) bool { return e.Name == env }); i >= 0 {
+		envVars[i] = envVar
+	} else {
+		envVars = append(envVars, envVar)
+	}
+
+	patchBytes, err := json.Marshal(
+		[]envPatch{
+			{
+				Op:    PatchOpAdd,
+				Path:  fmt.Sprintf(CollectorEnvJSONPath, index),
+				Value: envVars,
+			},
+		})
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %w", err)
+	}
+	if err := helper.Patch(ctx, types.JSONPatchType, patchBytes); err != nil {
+		return fmt.Errorf("failed to add secret to collector env: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package operator
 
-import mydecisivev1 "github.com/decisiveai/mydecisive-engine-operator/api/v1"
+import (
+	mydecisivev1 "github.com/decisiveai/mydecisive-engine-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
 
 type datalyzerPatch struct {
 	Op    string `json:"op"`
@@ -19,3 +22,21 @@ type otelConfigPatch struct {
 	Path  string `json:"path"`
 	Value string `json:"value"`
 }
+
+type collectorPatch struct {
+	Op    string                 `json:"op"`
+	Path  string                 `json:"path"`
+	Value mydecisivev1.Collector `json:"value"`
+}
+
+type namePatch struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value,omitempty"`
+}
+
+type envPatch struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value []corev1.EnvVar `json:"value"`
+}
@@ -0,0 +1,113 @@
+package otelconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	GraphASCII   = "ascii"
+	GraphDOT     = "dot"
+	GraphMermaid = "mermaid"
+)
+
+func GraphFormats() []string {
+	return []string{GraphASCII, GraphDOT, GraphMermaid}
+}
+
+// pipelineLabel names a pipeline and the filters muting it.
+func pipelineLabel(name string, mutedBy []string) string {
+	if len(mutedBy) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s (muted by %s)", name, strings.Join(mutedBy, ", "))
+}
+
+// DOT renders every pipeline as a cluster, muted maps pipelines to the
+// filters muting them.
+func (c *Config) DOT(muted map[string][]string) string {
+	var b strings.Builder
+	b.WriteString("digraph otel {\n  rankdir=LR;\n  node [shape=box];\n")
+	for i, pipeline := range c.Pipelines() {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n    label=%q;\n", i, pipelineLabel(pipeline, muted[pipeline]))
+		if len(muted[pipeline]) > 0 {
+			b.WriteString("    style=dashed;\n    color=red;\n")
+		}
+		c.edges(pipeline, func(kind, id string) string {
+			node := fmt.Sprintf("p%d_%s_%s", i, singular(kind), id)
+			fmt.Fprintf(&b, "    %q [label=%q];\n", node, id)
+			return node
+		}, func(from, to string) {
+			fmt.Fprintf(&b, "    %q -> %q;\n", from, to)
+		})
+		b.WriteString("  }\n")
+	}
+	for _, kind := range Kinds() {
+		for _, id := range c.Unused(kind) {
+			fmt.Fprintf(&b, "  %q [label=%q, style=dashed, color=orange];\n", "unused_"+singular(kind)+"_"+id, id+" (unused "+singular(kind)+")")
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the pipelines as a mermaid flowchart, see DOT.
+func (c *Config) Mermaid(muted map[string][]string) string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	ids := 0
+	for i, pipeline := range c.Pipelines() {
+		fmt.Fprintf(&b, "  subgraph p%d[%q]\n", i, pipelineLabel(pipeline, muted[pipeline]))
+		c.edges(pipeline, func(kind, id string) string {
+			ids++
+			node := fmt.Sprintf("n%d", ids)
+			fmt.Fprintf(&b, "    %s[%q]\n", node, id)
+			return node
+		}, func(from, to string) {
+			fmt.Fprintf(&b, "    %s --> %s\n", from, to)
+		})
+		b.WriteString("  end\n")
+		if len(muted[pipeline]) > 0 {
+			fmt.Fprintf(&b, "  class p%d muted\n", i)
+		}
+	}
+	for _, kind := range Kinds() {
+		for _, id := range c.Unused(kind) {
+			ids++
+			fmt.Fprintf(&b, "  n%d[%q]:::unused\n", ids, id+" (unused "+singular(kind)+")")
+		}
+	}
+	b.WriteString("  classDef muted stroke:#f00,stroke-dasharray:5 5\n")
+	b.WriteString("  classDef unused stroke:#f90,stroke-dasharray:5 5\n")
+	return b.String()
+}
+
+// edges links receivers to the processor chain and the chain to exporters.
+func (c *Config) edges(pipeline string, node func(kind, id string) string, edge func(from, to string)) {
+	var previous []string
+	for _, kind := range Kinds() {
+		var nodes []string
+		for _, id := range c.PipelineComponents(pipeline, kind) {
+			nodes = append(nodes, node(kind, id))
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+		if kind == Processors {
+			for j := 1; j < len(nodes); j++ {
+				edge(nodes[j-1], nodes[j])
+			}
+			for _, from := range previous {
+				edge(from, nodes[0])
+			}
+			previous = nodes[len(nodes)-1:]
+			continue
+		}
+		for _, from := range previous {
+			for _, to := range nodes {
+				edge(from, to)
+			}
+		}
+		previous = nodes
+	}
+}
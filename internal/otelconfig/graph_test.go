@@ -0,0 +1,153 @@
+package otelconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const graphConfig = `receivers:
+  otlp:
+  filelog:
+  hostmetrics:
+processors:
+  memory_limiter:
+  filter/noise:
+  batch:
+exporters:
+  debug:
+  otlp/vendor:
+  prometheus:
+service:
+  pipelines:
+    logs/foo:
+      receivers: [otlp, filelog]
+      processors: [memory_limiter, filter/noise, batch]
+      exporters: [debug, otlp/vendor]
+    traces:
+      receivers: [otlp]
+      exporters: [debug, otlp/vendor]
+`
+
+func TestEdges(t *testing.T) {
+	cfg, err := Parse(graphConfig)
+	require.NoError(t, err)
+	tests := []struct {
+		pipeline string
+		nodes    []string
+		edges    [][2]string
+	}{
+		{
+			pipeline: "logs/foo",
+			nodes:    []string{"otlp", "filelog", "memory_limiter", "filter/noise", "batch", "debug", "otlp/vendor"},
+			edges: [][2]string{
+				{"memory_limiter", "filter/noise"},
+				{"filter/noise", "batch"},
+				{"otlp", "memory_limiter"},
+				{"filelog", "memory_limiter"},
+				{"batch", "debug"},
+				{"batch", "otlp/vendor"},
+			},
+		},
+		{
+			pipeline: "traces",
+			nodes:    []string{"otlp", "debug", "otlp/vendor"},
+			edges: [][2]string{
+				{"otlp", "debug"},
+				{"otlp", "otlp/vendor"},
+			},
+		},
+		{pipeline: "metrics"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pipeline, func(t *testing.T) {
+			var nodes []string
+			var edges [][2]string
+			cfg.edges(tt.pipeline, func(_, id string) string {
+				nodes = append(nodes, id)
+				return id
+			}, func(from, to string) {
+				edges = append(edges, [2]string{from, to})
+			})
+			require.Equal(t, tt.nodes, nodes)
+			require.Equal(t, tt.edges, edges)
+		})
+	}
+}
+
+func TestPipelineLabel(t *testing.T) {
+	require.Equal(t, "logs/foo", pipelineLabel("logs/foo", nil))
+	require.Equal(t, "logs/foo (muted by noise, debug)", pipelineLabel("logs/foo", []string{"noise", "debug"}))
+}
+
+func TestDOT(t *testing.T) {
+	cfg, err := Parse(graphConfig)
+	require.NoError(t, err)
+	require.Equal(t, `digraph otel {
+  rankdir=LR;
+  node [shape=box];
+  subgraph cluster_0 {
+    label="logs/foo (muted by noise)";
+    style=dashed;
+    color=red;
+    "p0_receiver_otlp" [label="otlp"];
+    "p0_receiver_filelog" [label="filelog"];
+    "p0_processor_memory_limiter" [label="memory_limiter"];
+    "p0_processor_filter/noise" [label="filter/noise"];
+    "p0_processor_batch" [label="batch"];
+    "p0_processor_memory_limiter" -> "p0_processor_filter/noise";
+    "p0_processor_filter/noise" -> "p0_processor_batch";
+    "p0_receiver_otlp" -> "p0_processor_memory_limiter";
+    "p0_receiver_filelog" -> "p0_processor_memory_limiter";
+    "p0_exporter_debug" [label="debug"];
+    "p0_exporter_otlp/vendor" [label="otlp/vendor"];
+    "p0_processor_batch" -> "p0_exporter_debug";
+    "p0_processor_batch" -> "p0_exporter_otlp/vendor";
+  }
+  subgraph cluster_1 {
+    label="traces";
+    "p1_receiver_otlp" [label="otlp"];
+    "p1_exporter_debug" [label="debug"];
+    "p1_exporter_otlp/vendor" [label="otlp/vendor"];
+    "p1_receiver_otlp" -> "p1_exporter_debug";
+    "p1_receiver_otlp" -> "p1_exporter_otlp/vendor";
+  }
+  "unused_receiver_hostmetrics" [label="hostmetrics (unused receiver)", style=dashed, color=orange];
+  "unused_exporter_prometheus" [label="prometheus (unused exporter)", style=dashed, color=orange];
+}
+`, cfg.DOT(map[string][]string{"logs/foo": {"noise"}}))
+}
+
+func TestMermaid(t *testing.T) {
+	cfg, err := Parse(graphConfig)
+	require.NoError(t, err)
+	require.Equal(t, `flowchart LR
+  subgraph p0["logs/foo (muted by noise)"]
+    n1["otlp"]
+    n2["filelog"]
+    n3["memory_limiter"]
+    n4["filter/noise"]
+    n5["batch"]
+    n3 --> n4
+    n4 --> n5
+    n1 --> n3
+    n2 --> n3
+    n6["debug"]
+    n7["otlp/vendor"]
+    n5 --> n6
+    n5 --> n7
+  end
+  class p0 muted
+  subgraph p1["traces"]
+    n8["otlp"]
+    n9["debug"]
+    n10["otlp/vendor"]
+    n8 --> n9
+    n8 --> n10
+  end
+  n11["hostmetrics (unused receiver)"]:::unused
+  n12["prometheus (unused exporter)"]:::unused
+  classDef muted stroke:#f00,stroke-dasharray:5 5
+  classDef unused stroke:#f90,stroke-dasharray:5 5
+`, cfg.Mermaid(map[string][]string{"logs/foo": {"noise"}}))
+}
@@ -0,0 +1,307 @@
+package otelconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+
+	// disableComment turns rules off for the yaml node it is on and everything
+	// under it: `# mdai-lint-disable missing-batch,insecure-receiver`. Without
+	// rules it turns off every rule; disableFileComment does so for the file.
+	disableComment     = "mdai-lint-disable"
+	disableFileComment = "mdai-lint-disable-file"
+)
+
+// Finding is a problem found by a lint rule, located by its dotted path in
+// the config and its line.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Path     string   `json:"path"`
+	Line     int      `json:"line,omitempty"`
+
+	path []string
+}
+
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity
+	Check       func(*Config) []Finding
+}
+
+var rules = []Rule{
+	{
+		ID:          "debug-verbosity-detailed",
+		Description: "debug exporters at verbosity detailed flood the collector logs and should not run in production",
+		Severity:    SeverityWarning,
+		Check:       checkDebugVerbosity,
+	},
+	{
+		ID:          "missing-batch",
+		Description: "pipelines should batch telemetry with the batch processor",
+		Severity:    SeverityWarning,
+		Check:       checkProcessor("batch"),
+	},
+	{
+		ID:          "missing-memory-limiter",
+		Description: "pipelines should guard the collector memory with the memory_limiter processor",
+		Severity:    SeverityWarning,
+		Check:       checkProcessor("memory_limiter"),
+	},
+	{
+		ID:          "insecure-receiver",
+		Description: "receivers bound to every interface should be configured with tls",
+		Severity:    SeverityWarning,
+		Check:       checkInsecureReceivers,
+	},
+	{
+		ID:          "duplicate-pipeline",
+		Description: "pipelines of a signal with the same components duplicate telemetry",
+		Severity:    SeverityWarning,
+		Check:       checkDuplicatePipelines,
+	},
+	{
+		ID:          "unused-component",
+		Description: "components that no pipeline lists are never started",
+		Severity:    SeverityInfo,
+		Check:       checkUnused,
+	},
+}
+
+// Register adds a rule to the ones Lint runs.
+func Register(rule Rule) {
+	rules = append(rules, rule)
+}
+
+func Rules() []Rule {
+	return slices.Clone(rules)
+}
+
+// Lint runs every registered rule except the disabled ones and drops the
+// findings turned off by disable comments.
+func (c *Config) Lint(disabled ...string) []Finding {
+	fileDisabled := disabledRules(allComments(c.doc), disableFileComment)
+	var findings []Finding
+	for _, rule := range rules {
+		if slices.Contains(disabled, rule.ID) || matches(fileDisabled, rule.ID) {
+			continue
+		}
+		for _, finding := range rule.Check(c) {
+			if c.disabled(rule.ID, finding.path) {
+				continue
+			}
+			finding.Rule = rule.ID
+			if finding.Severity == "" {
+				finding.Severity = rule.Severity
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// finding locates a problem at path.
+func (c *Config) finding(message string, path ...string) Finding {
+	finding := Finding{Message: message, Path: strings.Join(path, "."), path: path}
+	node := c.root()
+	for _, key := range path {
+		k, v := entry(node, key)
+		if k == nil {
+			break
+		}
+		finding.Line, node = k.Line, v
+	}
+	return finding
+}
+
+func (c *Config) disabled(rule string, path []string) bool {
+	node := c.root()
+	for _, key := range path {
+		k, v := entry(node, key)
+		if k == nil {
+			return false
+		}
+		for _, comment := range []string{k.HeadComment, k.LineComment, v.HeadComment, v.LineComment} {
+			if matches(disabledRules([]string{comment}, disableComment), rule) {
+				return true
+			}
+		}
+		node = v
+	}
+	return false
+}
+
+// disabledRules uses "*" for every rule.
+func disabledRules(comments []string, directive string) []string {
+	var disabled []string
+	for _, comment := range comments {
+		for _, line := range strings.Split(comment, "\n") {
+			fields := strings.Fields(strings.TrimLeft(strings.TrimSpace(line), "#"))
+			if len(fields) == 0 || fields[0] != directive {
+				continue
+			}
+			if len(fields) == 1 {
+				return []string{"*"}
+			}
+			for _, field := range fields[1:] {
+				disabled = append(disabled, strings.Split(field, ",")...)
+			}
+		}
+	}
+	return disabled
+}
+
+func matches(disabled []string, rule string) bool {
+	return slices.Contains(disabled, "*") || slices.Contains(disabled, rule)
+}
+
+func allComments(node *yaml.Node) []string {
+	comments := []string{node.HeadComment, node.LineComment, node.FootComment}
+	for _, child := range node.Content {
+		comments = append(comments, allComments(child)...)
+	}
+	return comments
+}
+
+// componentType is the type part of a component id such as otlp/vendor.
+func componentType(id string) string {
+	t, _, _ := strings.Cut(id, "/")
+	return t
+}
+
+func checkDebugVerbosity(c *Config) []Finding {
+	var findings []Finding
+	for _, id := range c.Components(Exporters) {
+		if componentType(id) != "debug" {
+			continue
+		}
+		if verbosity := lookup(lookup(lookup(c.root(), Exporters), id), "verbosity"); verbosity != nil && verbosity.Value == "detailed" {
+			findings = append(findings, c.finding(fmt.Sprintf("exporter %s has verbosity detailed", id), Exporters, id, "verbosity"))
+		}
+	}
+	return findings
+}
+
+func checkProcessor(processorType string) func(*Config) []Finding {
+	return func(c *Config) []Finding {
+		var findings []Finding
+		for _, pipeline := range c.Pipelines() {
+			if !slices.ContainsFunc(c.PipelineComponents(pipeline, Processors), func(id string) bool { return componentType(id) == processorType }) {
+				findings = append(findings, c.finding(fmt.Sprintf("pipeline %s has no %s processor", pipeline, processorType), "service", "pipelines", pipeline))
+			}
+		}
+		return findings
+	}
+}
+
+func checkInsecureReceivers(c *Config) []Finding {
+	var findings []Finding
+	var walk func(node *yaml.Node, path []string)
+	walk = func(node *yaml.Node, path []string) {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return
+		}
+		endpoint := lookup(node, "endpoint")
+		if endpoint != nil && lookup(node, "tls") == nil {
+			host := endpoint.Value
+			if i := strings.LastIndex(host, ":"); i >= 0 {
+				host = host[:i]
+			}
+			if host == "" || host == "0.0.0.0" || host == "[::]" {
+				findings = append(findings, c.finding(fmt.Sprintf("%s listens on %s without tls", path[1], endpoint.Value), append(slices.Clone(path), "endpoint")...))
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walk(node.Content[i+1], append(slices.Clone(path), node.Content[i].Value))
+		}
+	}
+	for _, id := range c.Components(Receivers) {
+		walk(lookup(lookup(c.root(), Receivers), id), []string{Receivers, id})
+	}
+	return findings
+}
+
+func checkDuplicatePipelines(c *Config) []Finding {
+	var findings []Finding
+	seen := make(map[string]string)
+	for _, pipeline := range c.Pipelines() {
+		signal, _, _ := strings.Cut(pipeline, "/")
+		key := signal
+		for _, kind := range Kinds() {
+			key += "|" + strings.Join(c.PipelineComponents(pipeline, kind), ",")
+		}
+		if first, ok := seen[key]; ok {
+			findings = append(findings, c.finding(fmt.Sprintf("pipeline %s duplicates pipeline %s", pipeline, first), "service", "pipelines", pipeline))
+			continue
+		}
+		seen[key] = pipeline
+	}
+	return findings
+}
+
+func checkUnused(c *Config) []Finding {
+	var findings []Finding
+	for _, kind := range Kinds() {
+		for _, id := range c.Unused(kind) {
+			findings = append(findings, c.finding(fmt.Sprintf("%s %s is not used by any pipeline", singular(kind), id), kind, id))
+		}
+	}
+	return findings
+}
+
+// SARIF renders findings as a SARIF 2.1.0 log for code scanning tools, with
+// uri naming the linted config.
+func SARIF(findings []Finding, uri string) ([]byte, error) {
+	levels := map[Severity]string{SeverityError: "error", SeverityWarning: "warning", SeverityInfo: "note"}
+
+	type message struct {
+		Text string `json:"text"`
+	}
+	driverRules := make([]map[string]any, 0, len(rules))
+	for _, rule := range rules {
+		driverRules = append(driverRules, map[string]any{
+			"id":                   rule.ID,
+			"shortDescription":     message{rule.Description},
+			"defaultConfiguration": map[string]string{"level": levels[rule.Severity]},
+		})
+	}
+	results := make([]map[string]any, 0, len(findings))
+	for _, finding := range findings {
+		location := map[string]any{"artifactLocation": map[string]string{"uri": uri}}
+		if finding.Line > 0 {
+			location["region"] = map[string]int{"startLine": finding.Line}
+		}
+		results = append(results, map[string]any{
+			"ruleId":    finding.Rule,
+			"level":     levels[finding.Severity],
+			"message":   message{finding.Message},
+			"locations": []map[string]any{{"physicalLocation": location}},
+		})
+	}
+
+	data, err := json.MarshalIndent(map[string]any{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []map[string]any{{
+			"tool":    map[string]any{"driver": map[string]any{"name": "mdai", "rules": driverRules}},
+			"results": results,
+		}},
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sarif: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,179 @@
+package otelconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		disabled []string
+		want     []string
+	}{
+		{
+			name: "problems",
+			config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+exporters:
+  debug:
+    verbosity: detailed
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      exporters: [debug]
+`,
+			want: []string{"debug-verbosity-detailed", "missing-batch", "missing-memory-limiter", "insecure-receiver"},
+		},
+		{
+			name: "disabled by argument",
+			config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+exporters:
+  debug:
+    verbosity: detailed
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      exporters: [debug]
+`,
+			disabled: []string{"missing-batch", "missing-memory-limiter"},
+			want:     []string{"debug-verbosity-detailed", "insecure-receiver"},
+		},
+		{
+			name: "disabled by line comment",
+			config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317 # mdai-lint-disable insecure-receiver
+exporters:
+  debug:
+    verbosity: detailed
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      exporters: [debug]
+`,
+			disabled: []string{"missing-batch", "missing-memory-limiter"},
+			want:     []string{"debug-verbosity-detailed"},
+		},
+		{
+			name: "disabled by head comment for the subtree",
+			config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+exporters:
+  debug:
+    verbosity: detailed
+service:
+  pipelines:
+    # mdai-lint-disable missing-batch,missing-memory-limiter
+    logs:
+      receivers: [otlp]
+      exporters: [debug]
+`,
+			want: []string{"debug-verbosity-detailed", "insecure-receiver"},
+		},
+		{
+			name: "every rule disabled for a node",
+			config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+exporters:
+  # mdai-lint-disable
+  debug:
+    verbosity: detailed
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      exporters: [debug]
+`,
+			disabled: []string{"missing-batch", "missing-memory-limiter"},
+			want:     []string{"insecure-receiver"},
+		},
+		{
+			name: "disabled for the file",
+			config: `# mdai-lint-disable-file insecure-receiver debug-verbosity-detailed
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+exporters:
+  debug:
+    verbosity: detailed
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      exporters: [debug]
+`,
+			want: []string{"missing-batch", "missing-memory-limiter"},
+		},
+		{
+			name: "tls and a pod ip",
+			config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: ${env:MY_POD_IP}:4317
+      http:
+        endpoint: 0.0.0.0:4318
+        tls:
+          cert_file: cert.pem
+processors:
+  memory_limiter:
+  batch:
+exporters:
+  debug:
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      processors: [memory_limiter, batch]
+      exporters: [debug]
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Parse(tt.config)
+			require.NoError(t, err)
+			var rules []string
+			for _, finding := range cfg.Lint(tt.disabled...) {
+				rules = append(rules, finding.Rule)
+			}
+			require.Equal(t, tt.want, rules)
+		})
+	}
+}
+
+func TestLintFindingLine(t *testing.T) {
+	cfg, err := Parse(`exporters:
+  debug:
+    verbosity: detailed
+`)
+	require.NoError(t, err)
+	findings := cfg.Lint("unused-component")
+	require.Len(t, findings, 1)
+	require.Equal(t, "exporters.debug.verbosity", findings[0].Path)
+	require.Equal(t, 3, findings[0].Line)
+	require.Equal(t, SeverityWarning, findings[0].Severity)
+}
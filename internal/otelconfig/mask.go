@@ -0,0 +1,69 @@
+package otelconfig
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const Masked = "******"
+
+var (
+	credentialKeys = []string{"password", "passwd", "secret", "token", "apikey", "authorization", "credential", "privatekey", "accesskey"}
+	// credentialValue matches bearer and basic auth values, whose token has a
+	// digit or symbol unlike prose, and well known key prefixes.
+	credentialValue = regexp.MustCompile(`^(?i:bearer|basic)\s+[-._~+/A-Za-z0-9]*[-._~+/0-9][-._~+/A-Za-z0-9]*=*$|^(sk-[-_A-Za-z0-9]{16,}|gh[pousr]_[A-Za-z0-9]{16,}|xox[abp]-[-A-Za-z0-9]{10,}|AKIA[0-9A-Z]{16})$`)
+	envReference    = regexp.MustCompile(`^\$\{(env:)?[A-Za-z_][A-Za-z0-9_]*(:-[^}]*)?\}$`)
+)
+
+// Mask masks values under credential keys and auth header values, but not
+// env references like ${env:API_KEY}.
+func (c *Config) Mask() int {
+	return mask(c.root())
+}
+
+func mask(node *yaml.Node) int {
+	masked := 0
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if value.Kind == yaml.ScalarNode && isCredentialKey(key.Value) && maskValue(value) {
+				masked++
+				continue
+			}
+			masked += mask(value)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			masked += mask(item)
+		}
+	case yaml.ScalarNode:
+		if credentialValue.MatchString(node.Value) && maskValue(node) {
+			masked++
+		}
+	}
+	return masked
+}
+
+func isCredentialKey(key string) bool {
+	key = strings.NewReplacer("-", "", "_", "", ".", "").Replace(strings.ToLower(key))
+	if strings.HasSuffix(key, "file") || strings.HasSuffix(key, "path") {
+		return false
+	}
+	for _, credential := range credentialKeys {
+		if strings.Contains(key, credential) {
+			return true
+		}
+	}
+	return false
+}
+
+func maskValue(node *yaml.Node) bool {
+	if node.Tag == "!!null" || node.Value == "" || envReference.MatchString(node.Value) {
+		return false
+	}
+	node.Value, node.Tag, node.Style = Masked, "!!str", 0
+	return true
+}
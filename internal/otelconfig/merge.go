@@ -0,0 +1,122 @@
+package otelconfig
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fragment is part of an otel config and the file it came from.
+type Fragment struct {
+	File string
+	Data string
+}
+
+// Merge unions pipeline component lists in order, other keys that two
+// fragments set differently conflict.
+func Merge(fragments []Fragment) (*Config, error) {
+	if len(fragments) == 0 {
+		return nil, errors.New("no otel config fragments")
+	}
+	var merged *Config
+	owners := make(map[string]string)
+	var errs []error
+	for _, fragment := range fragments {
+		cfg, err := Parse(fragment.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fragment.File, err)
+		}
+		if merged == nil {
+			merged = cfg
+			owners[""] = fragment.File
+			continue
+		}
+		errs = append(errs, mergeNode(merged.root(), cfg.root(), nil, fragment.File, owners)...)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("conflicting otel config fragments:\n%w", errors.Join(errs...))
+	}
+	return merged, nil
+}
+
+func mergeNode(dst, src *yaml.Node, path []string, file string, owners map[string]string) []error {
+	switch {
+	case isNull(src):
+		return nil
+	case isNull(dst):
+		*dst = *src
+		owners[strings.Join(path, ".")] = file
+		return nil
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		var errs []error
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+			childPath := append(slices.Clone(path), key.Value)
+			if existing := lookup(dst, key.Value); existing != nil {
+				errs = append(errs, mergeNode(existing, value, childPath, file, owners)...)
+				continue
+			}
+			dst.Content = append(dst.Content, key, value)
+			owners[strings.Join(childPath, ".")] = file
+		}
+		return errs
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode && isPipelineList(path):
+		for i, item := range src.Content {
+			if slices.Contains(sequenceValues(dst), item.Value) {
+				continue
+			}
+			index := len(dst.Content)
+			for _, next := range src.Content[i+1:] {
+				if j := slices.Index(sequenceValues(dst), next.Value); j >= 0 {
+					index = j
+					break
+				}
+			}
+			dst.Content = slices.Insert(dst.Content, index, item)
+		}
+		return nil
+	case dst.Kind == src.Kind && equalNodes(dst, src):
+		return nil
+	case dst.Kind == yaml.ScalarNode && src.Kind == yaml.ScalarNode:
+		return []error{fmt.Errorf("%s: %s: %s conflicts with %s from %s", file, strings.Join(path, "."), src.Value, dst.Value, owner(path, owners))}
+	default:
+		return []error{fmt.Errorf("%s: %s conflicts with the value from %s", file, strings.Join(path, "."), owner(path, owners))}
+	}
+}
+
+// isPipelineList reports whether path is service.pipelines.NAME.KIND.
+func isPipelineList(path []string) bool {
+	return len(path) == 4 && path[0] == "service" && path[1] == "pipelines" && slices.Contains(Kinds(), path[3]) //nolint: mnd
+}
+
+// owner returns the file that set path or its closest parent.
+func owner(path []string, owners map[string]string) string {
+	for i := len(path); i >= 0; i-- {
+		if file, ok := owners[strings.Join(path[:i], ".")]; ok {
+			return file
+		}
+	}
+	return ""
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func equalNodes(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+	if a.Kind == yaml.ScalarNode {
+		return a.Value == b.Value
+	}
+	for i := range a.Content {
+		if !equalNodes(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
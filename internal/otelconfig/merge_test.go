@@ -0,0 +1,130 @@
+package otelconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const baseFragment = `receivers:
+  otlp:
+    protocols:
+      grpc:
+processors:
+  memory_limiter:
+    limit_percentage: 80
+  batch:
+exporters:
+  debug:
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      processors: [memory_limiter, batch]
+      exporters: [debug]
+`
+
+func TestMergePipelineLists(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment string
+		want     []string
+	}{
+		{
+			name: "before the next listed component",
+			fragment: `processors:
+  filter:
+service:
+  pipelines:
+    logs:
+      processors: [filter, batch]
+`,
+			want: []string{"memory_limiter", "filter", "batch"},
+		},
+		{
+			name: "last without a next component",
+			fragment: `processors:
+  resource:
+service:
+  pipelines:
+    logs:
+      processors: [batch, resource]
+`,
+			want: []string{"memory_limiter", "batch", "resource"},
+		},
+		{
+			name: "already listed",
+			fragment: `service:
+  pipelines:
+    logs:
+      processors: [batch]
+`,
+			want: []string{"memory_limiter", "batch"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Merge([]Fragment{{File: "base.yaml", Data: baseFragment}, {File: "extra.yaml", Data: tt.fragment}})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, cfg.PipelineComponents("logs", Processors))
+			require.NoError(t, cfg.Validate())
+		})
+	}
+}
+
+func TestMergeConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment string
+		err      string
+	}{
+		{
+			name: "same value",
+			fragment: `processors:
+  memory_limiter:
+    limit_percentage: 80
+`,
+		},
+		{
+			name: "new key",
+			fragment: `processors:
+  memory_limiter:
+    check_interval: 1s
+`,
+		},
+		{
+			name: "scalar",
+			fragment: `processors:
+  memory_limiter:
+    limit_percentage: 90
+`,
+			err: "conflicting otel config fragments:\nextra.yaml: processors.memory_limiter.limit_percentage: 90 conflicts with 80 from base.yaml",
+		},
+		{
+			name: "kind",
+			fragment: `processors:
+  memory_limiter: [limit_percentage]
+`,
+			err: "conflicting otel config fragments:\nextra.yaml: processors.memory_limiter conflicts with the value from base.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Merge([]Fragment{{File: "base.yaml", Data: baseFragment}, {File: "extra.yaml", Data: tt.fragment}})
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestMergeConflictOwner(t *testing.T) {
+	_, err := Merge([]Fragment{
+		{File: "base.yaml", Data: baseFragment},
+		{File: "exporter.yaml", Data: "exporters:\n  otlp:\n    endpoint: a:4317\n"},
+		{File: "other.yaml", Data: "exporters:\n  otlp:\n    endpoint: b:4317\n"},
+	})
+	require.EqualError(t, err, "conflicting otel config fragments:\nother.yaml: exporters.otlp.endpoint: b:4317 conflicts with a:4317 from exporter.yaml")
+}
@@ -0,0 +1,397 @@
+package otelconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	Receivers  = "receivers"
+	Processors = "processors"
+	Exporters  = "exporters"
+	Connectors = "connectors"
+	Extensions = "extensions"
+
+	PositionReceivers = "receivers"
+	PositionExporters = "exporters"
+)
+
+func Kinds() []string {
+	return []string{Receivers, Processors, Exporters}
+}
+
+func Signals() []string {
+	return []string{"traces", "metrics", "logs"}
+}
+
+// componentSignals lists single signal types, other types support every signal.
+var componentSignals = map[string][]string{
+	"hostmetrics":           {"metrics"},
+	"kubeletstats":          {"metrics"},
+	"k8s_cluster":           {"metrics"},
+	"prometheus":            {"metrics"},
+	"prometheusremotewrite": {"metrics"},
+	"cumulativetodelta":     {"metrics"},
+	"deltatorate":           {"metrics"},
+	"metricstransform":      {"metrics"},
+	"filelog":               {"logs"},
+	"journald":              {"logs"},
+	"k8sobjects":            {"logs"},
+	"k8s_events":            {"logs"},
+	"syslog":                {"logs"},
+	"jaeger":                {"traces"},
+	"zipkin":                {"traces"},
+	"tail_sampling":         {"traces"},
+	"groupbytrace":          {"traces"},
+}
+
+// SupportsSignal reports whether a component id such as prometheus/internal
+// handles signal.
+func SupportsSignal(id, signal string) bool {
+	signals, ok := componentSignals[componentType(id)]
+	return !ok || slices.Contains(signals, signal)
+}
+
+// Config is an otel collector config kept as a yaml node tree, so edits
+// preserve the comments and the order of everything they do not touch.
+type Config struct {
+	doc *yaml.Node
+}
+
+func Parse(data string) (*Config, error) {
+	doc := new(yaml.Node)
+	if err := yaml.Unmarshal([]byte(data), doc); err != nil {
+		return nil, fmt.Errorf("failed to parse otel config: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("otel config is not a mapping")
+	}
+	return &Config{doc: doc}, nil
+}
+
+func (c *Config) String() (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2) //nolint: mnd
+	if err := encoder.Encode(c.doc); err != nil {
+		return "", fmt.Errorf("failed to marshal otel config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to marshal otel config: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (c *Config) root() *yaml.Node {
+	return c.doc.Content[0]
+}
+
+func (c *Config) Components(kind string) []string {
+	section := lookup(c.root(), kind)
+	if section == nil || section.Kind != yaml.MappingNode {
+		return nil
+	}
+	ids := make([]string, 0, len(section.Content)/2) //nolint: mnd
+	for i := 0; i < len(section.Content); i += 2 {
+		ids = append(ids, section.Content[i].Value)
+	}
+	return ids
+}
+
+func (c *Config) Pipelines() []string {
+	pipelines := c.pipelines()
+	if pipelines == nil {
+		return nil
+	}
+	names := make([]string, 0, len(pipelines.Content)/2) //nolint: mnd
+	for i := 0; i < len(pipelines.Content); i += 2 {
+		names = append(names, pipelines.Content[i].Value)
+	}
+	return names
+}
+
+func (c *Config) PipelineComponents(pipeline, kind string) []string {
+	pipelines := c.pipelines()
+	if pipelines == nil {
+		return nil
+	}
+	return sequenceValues(lookup(lookup(pipelines, pipeline), kind))
+}
+
+// AddComponent takes settings as dotted keys, for example tls.insecure=true.
+func (c *Config) AddComponent(kind, id string, settings map[string]string) error {
+	if !slices.Contains(Kinds(), kind) {
+		return fmt.Errorf("unknown component kind %s", kind)
+	}
+	section := ensureMapping(c.root(), kind)
+	if lookup(section, id) != nil {
+		return fmt.Errorf("%s %s already exists", singular(kind), id)
+	}
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if value.Kind != yaml.MappingNode {
+			value = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		node := value
+		path := strings.Split(key, ".")
+		for _, part := range path[:len(path)-1] {
+			node = ensureMapping(node, part)
+		}
+		set(node, path[len(path)-1], &yaml.Node{Kind: yaml.ScalarNode, Value: settings[key]})
+	}
+	set(section, id, value)
+	return nil
+}
+
+func (c *Config) RemoveComponent(kind, id string) ([]string, error) {
+	section := lookup(c.root(), kind)
+	if section == nil || !remove(section, id) {
+		return nil, fmt.Errorf("%s %s not found", singular(kind), id)
+	}
+	if len(section.Content) == 0 {
+		remove(c.root(), kind)
+	}
+	var changed []string
+	for _, name := range c.Pipelines() {
+		list := lookup(lookup(c.pipelines(), name), kind)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		before := len(list.Content)
+		list.Content = slices.DeleteFunc(list.Content, func(n *yaml.Node) bool { return n.Value == id })
+		if len(list.Content) != before {
+			changed = append(changed, name)
+		}
+	}
+	return changed, nil
+}
+
+// AddToPipeline positions processors at "", receivers, exporters, or
+// "before:ID" or "after:ID"; other kinds are appended.
+func (c *Config) AddToPipeline(pipeline, kind, id, position string) error {
+	pipelines := c.pipelines()
+	p := lookup(pipelines, pipeline)
+	if p == nil {
+		return fmt.Errorf("pipeline %s not found", pipeline)
+	}
+	if p.Kind != yaml.MappingNode {
+		p.Kind, p.Tag, p.Value = yaml.MappingNode, "!!map", ""
+	}
+	list := lookup(p, kind)
+	if list == nil || list.Kind != yaml.SequenceNode {
+		list = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		set(p, kind, list)
+		if kind == Processors {
+			moveBefore(p, kind, Exporters)
+		}
+	}
+	if slices.Contains(sequenceValues(list), id) {
+		return nil
+	}
+	item := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: id}
+
+	index := len(list.Content)
+	switch where, anchor, _ := strings.Cut(position, ":"); {
+	case position == "" || position == PositionExporters:
+	case position == PositionReceivers:
+		index = 0
+	case where == "before" || where == "after":
+		index = slices.IndexFunc(list.Content, func(n *yaml.Node) bool { return n.Value == anchor })
+		if index < 0 {
+			return fmt.Errorf("%s %s is not in pipeline %s", singular(kind), anchor, pipeline)
+		}
+		if where == "after" {
+			index++
+		}
+	default:
+		return fmt.Errorf("invalid position %s", position)
+	}
+	list.Content = slices.Insert(list.Content, index, item)
+	return nil
+}
+
+func (c *Config) AddPipeline(name string, components map[string][]string) error {
+	signal, _, _ := strings.Cut(name, "/")
+	if !slices.Contains(Signals(), signal) {
+		return fmt.Errorf("invalid pipeline %s, expected %s[/NAME]", name, strings.Join(Signals(), "|"))
+	}
+	pipelines := ensureMapping(ensureMapping(c.root(), "service"), "pipelines")
+	if lookup(pipelines, name) != nil {
+		return fmt.Errorf("pipeline %s already exists", name)
+	}
+	pipeline := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, kind := range Kinds() {
+		if len(components[kind]) == 0 {
+			continue
+		}
+		list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, id := range components[kind] {
+			list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: id})
+		}
+		set(pipeline, kind, list)
+	}
+	set(pipelines, name, pipeline)
+	return nil
+}
+
+func (c *Config) RemovePipeline(name string) error {
+	if pipelines := c.pipelines(); pipelines == nil || !remove(pipelines, name) {
+		return fmt.Errorf("pipeline %s not found", name)
+	}
+	return nil
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	for _, section := range []string{Receivers, Processors, Exporters, Connectors, Extensions, "service"} {
+		if node := lookup(c.root(), section); node != nil && node.Kind != yaml.MappingNode && node.Tag != "!!null" {
+			errs = append(errs, fmt.Errorf("%s is not a mapping", section))
+		}
+	}
+	pipelines := c.Pipelines()
+	if len(pipelines) == 0 {
+		errs = append(errs, errors.New("service.pipelines has no pipelines"))
+	}
+	connectors := c.Components(Connectors)
+	for _, name := range pipelines {
+		signal, _, _ := strings.Cut(name, "/")
+		if !slices.Contains(Signals(), signal) {
+			errs = append(errs, fmt.Errorf("pipeline %s: unknown signal %s", name, signal))
+		}
+		for _, kind := range Kinds() {
+			ids := c.PipelineComponents(name, kind)
+			if kind != Processors && len(ids) == 0 {
+				errs = append(errs, fmt.Errorf("pipeline %s: no %s", name, kind))
+			}
+			defined := c.Components(kind)
+			for _, id := range ids {
+				switch {
+				case slices.Contains(defined, id):
+					if !SupportsSignal(id, signal) {
+						errs = append(errs, fmt.Errorf("pipeline %s: %s %s does not support %s", name, singular(kind), id, signal))
+					}
+				case kind == Processors || !slices.Contains(connectors, id):
+					errs = append(errs, fmt.Errorf("pipeline %s: %s %s is not defined", name, singular(kind), id))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Config) pipelines() *yaml.Node {
+	return lookup(lookup(c.root(), "service"), "pipelines")
+}
+
+func singular(kind string) string {
+	return strings.TrimSuffix(kind, "s")
+}
+
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	_, value := entry(mapping, key)
+	return value
+}
+
+func entry(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func set(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func remove(mapping *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = slices.Delete(mapping.Content, i, i+2) //nolint: mnd
+			return true
+		}
+	}
+	return false
+}
+
+func moveBefore(mapping *yaml.Node, key, other string) {
+	from, to := -1, -1
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		switch mapping.Content[i].Value {
+		case key:
+			from = i
+		case other:
+			to = i
+		}
+	}
+	if from < 0 || to < 0 || from < to {
+		return
+	}
+	pair := slices.Clone(mapping.Content[from : from+2])
+	mapping.Content = slices.Delete(mapping.Content, from, from+2) //nolint: mnd
+	mapping.Content = slices.Insert(mapping.Content, to, pair...)
+}
+
+// ensureMapping turns an empty value such as `batch:` into a mapping.
+func ensureMapping(mapping *yaml.Node, key string) *yaml.Node {
+	node := lookup(mapping, key)
+	if node == nil {
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		set(mapping, key, node)
+	}
+	if node.Kind != yaml.MappingNode {
+		node.Kind, node.Tag, node.Value, node.Style = yaml.MappingNode, "!!map", "", 0
+	}
+	return node
+}
+
+func sequenceValues(node *yaml.Node) []string {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	values := make([]string, 0, len(node.Content))
+	for _, item := range node.Content {
+		values = append(values, item.Value)
+	}
+	return values
+}
+
+func (c *Config) Unused(kind string) []string {
+	var unused []string
+	for _, id := range c.Components(kind) {
+		used := false
+		for _, pipeline := range c.Pipelines() {
+			if slices.Contains(c.PipelineComponents(pipeline, kind), id) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			unused = append(unused, id)
+		}
+	}
+	return unused
+}
@@ -0,0 +1,81 @@
+package otelconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `receivers:
+  otlp:
+    protocols:
+      grpc:
+processors:
+  memory_limiter:
+  batch:
+exporters:
+  debug:
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      processors: [memory_limiter, batch]
+      exporters: [debug]
+`
+
+func TestAddToPipeline(t *testing.T) {
+	tests := []struct {
+		name     string
+		position string
+		want     []string
+		err      string
+	}{
+		{name: "default", want: []string{"memory_limiter", "batch", "filter"}},
+		{name: "exporters", position: PositionExporters, want: []string{"memory_limiter", "batch", "filter"}},
+		{name: "receivers", position: PositionReceivers, want: []string{"filter", "memory_limiter", "batch"}},
+		{name: "before", position: "before:batch", want: []string{"memory_limiter", "filter", "batch"}},
+		{name: "after", position: "after:memory_limiter", want: []string{"memory_limiter", "filter", "batch"}},
+		{name: "after last", position: "after:batch", want: []string{"memory_limiter", "batch", "filter"}},
+		{name: "missing anchor", position: "before:resource", err: "processor resource is not in pipeline logs"},
+		{name: "invalid position", position: "middle", err: "invalid position middle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Parse(testConfig)
+			require.NoError(t, err)
+			err = cfg.AddToPipeline("logs", Processors, "filter", tt.position)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, cfg.PipelineComponents("logs", Processors))
+		})
+	}
+}
+
+func TestValidateSignals(t *testing.T) {
+	tests := []struct {
+		name     string
+		receiver string
+		err      string
+	}{
+		{name: "any signal", receiver: "otlp/other"},
+		{name: "matching signal", receiver: "filelog"},
+		{name: "other signal", receiver: "hostmetrics", err: "pipeline logs: receiver hostmetrics does not support logs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Parse(testConfig)
+			require.NoError(t, err)
+			require.NoError(t, cfg.AddComponent(Receivers, tt.receiver, nil))
+			require.NoError(t, cfg.AddToPipeline("logs", Receivers, tt.receiver, ""))
+			err = cfg.Validate()
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
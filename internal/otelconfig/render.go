@@ -0,0 +1,67 @@
+package otelconfig
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// Render executes a config template with the sprig functions and toYaml;
+// referencing a variable that is not set is an error.
+func Render(name, text string, vars map[string]any) (string, error) {
+	funcs := sprig.TxtFuncMap()
+	funcs["toYaml"] = func(v any) (string, error) {
+		data, err := yaml.Marshal(v)
+		return strings.TrimSuffix(string(data), "\n"), err
+	}
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// Vars reads the template variables of a vars file, if any, and overrides
+// them with KEY=VALUE pairs whose dotted keys set nested variables.
+func Vars(file string, pairs []string) (map[string]any, error) {
+	vars := make(map[string]any)
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to read vars file "%s": %w`, file, err)
+		}
+		if err := yaml.Unmarshal(data, &vars); err != nil {
+			return nil, fmt.Errorf(`failed to parse vars file "%s": %w`, file, err)
+		}
+		if vars == nil {
+			vars = make(map[string]any)
+		}
+	}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid var %s, expected KEY=VALUE", pair)
+		}
+		node := vars
+		path := strings.Split(key, ".")
+		for _, part := range path[:len(path)-1] {
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[part] = child
+			}
+			node = child
+		}
+		node[path[len(path)-1]] = value
+	}
+	return vars, nil
+}
@@ -0,0 +1,107 @@
+package otelconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		vars map[string]any
+		want string
+		err  string
+	}{
+		{
+			name: "vars",
+			text: "endpoint: {{ .endpoint }}:{{ .port | default 4317 }}",
+			vars: map[string]any{"endpoint": "otel.example.com", "port": ""},
+			want: "endpoint: otel.example.com:4317",
+		},
+		{
+			name: "nested vars",
+			text: "verbosity: {{ .debug.verbosity }}",
+			vars: map[string]any{"debug": map[string]any{"verbosity": "basic"}},
+			want: "verbosity: basic",
+		},
+		{
+			name: "toYaml",
+			text: "exporters: {{ toYaml .exporters }}",
+			vars: map[string]any{"exporters": []string{"debug"}},
+			want: "exporters: - debug",
+		},
+		{
+			name: "missing key",
+			text: "endpoint: {{ .endpoint }}",
+			vars: map[string]any{},
+			err:  `map has no entry for key "endpoint"`,
+		},
+		{
+			name: "missing nested key",
+			text: "verbosity: {{ .debug.verbosity }}",
+			vars: map[string]any{"debug": map[string]any{}},
+			err:  `map has no entry for key "verbosity"`,
+		},
+		{
+			name: "invalid template",
+			text: "endpoint: {{ .endpoint",
+			err:  "failed to parse template config.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render("config.yaml", tt.text, tt.vars)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVars(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "vars.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("endpoint: otel.example.com\ndebug:\n  verbosity: detailed\n"), 0o600))
+
+	tests := []struct {
+		name  string
+		file  string
+		pairs []string
+		want  map[string]any
+		err   string
+	}{
+		{
+			name:  "pairs",
+			pairs: []string{"endpoint=otel.example.com", "debug.verbosity=basic"},
+			want:  map[string]any{"endpoint": "otel.example.com", "debug": map[string]any{"verbosity": "basic"}},
+		},
+		{
+			name:  "pairs override the file",
+			file:  file,
+			pairs: []string{"debug.verbosity=basic"},
+			want:  map[string]any{"endpoint": "otel.example.com", "debug": map[string]any{"verbosity": "basic"}},
+		},
+		{
+			name:  "invalid pair",
+			pairs: []string{"endpoint"},
+			err:   "invalid var endpoint, expected KEY=VALUE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars, err := Vars(tt.file, tt.pairs)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, vars)
+		})
+	}
+}
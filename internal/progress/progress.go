@@ -0,0 +1,160 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+)
+
+const pollInterval = time.Second
+
+var ErrInterrupted = errors.New("interrupted")
+
+type Styles struct {
+	Title   lipgloss.Style
+	Spinner lipgloss.Style
+	Ready   lipgloss.Style
+	Pending lipgloss.Style
+	Status  lipgloss.Style
+}
+
+func Run(ctx context.Context, watcher *Watcher, title string, styles Styles) error {
+	if isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+		return runTUI(ctx, watcher, title, styles)
+	}
+	return runPlain(ctx, watcher, title, os.Stdout)
+}
+
+func runPlain(ctx context.Context, watcher *Watcher, title string, out io.Writer) error {
+	start := time.Now()
+	seen := map[string]Component{}
+	report := func(snapshotCtx context.Context) {
+		components, _ := watcher.Snapshot(snapshotCtx)
+		for _, c := range components {
+			if last, ok := seen[c.String()]; ok && last == c {
+				continue
+			}
+			seen[c.String()] = c
+			_, _ = fmt.Fprintf(out, "[%6s] %s %s: %s\n", time.Since(start).Truncate(time.Second), mark(c), c, c.Status)
+		}
+	}
+
+	_, _ = fmt.Fprintln(out, title)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			report(context.WithoutCancel(ctx))
+			return nil
+		case <-ticker.C:
+			report(ctx)
+		}
+	}
+}
+
+func runTUI(ctx context.Context, watcher *Watcher, title string, styles Styles) error {
+	m := model{
+		title:   title,
+		styles:  styles,
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(styles.Spinner)),
+	}
+	p := tea.NewProgram(m)
+
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				components, _ := watcher.Snapshot(context.WithoutCancel(ctx))
+				p.Send(snapshotMsg(components))
+				p.Send(doneMsg{})
+				return
+			case <-ticker.C:
+				components, err := watcher.Snapshot(ctx)
+				if err == nil {
+					p.Send(snapshotMsg(components))
+				}
+			}
+		}
+	}()
+
+	final, err := p.Run()
+	if err != nil {
+		return err
+	}
+	if final.(model).interrupted {
+		return ErrInterrupted
+	}
+	return nil
+}
+
+type (
+	snapshotMsg []Component
+	doneMsg     struct{}
+)
+
+type model struct {
+	title       string
+	styles      Styles
+	spinner     spinner.Model
+	components  []Component
+	done        bool
+	interrupted bool
+}
+
+func (m model) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			m.interrupted = true
+			return m, tea.Quit
+		}
+	case snapshotMsg:
+		m.components = msg
+	case doneMsg:
+		m.done = true
+		return m, tea.Quit
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m model) View() string {
+	var sb strings.Builder
+	if !m.done {
+		sb.WriteString(m.spinner.View() + " " + m.styles.Title.Render(m.title) + "\n")
+	}
+	for _, c := range m.components {
+		style := m.styles.Pending
+		if c.Ready {
+			style = m.styles.Ready
+		}
+		sb.WriteString(style.Render(mark(c)) + " " + c.String() + " " + m.styles.Status.Render(c.Status) + "\n")
+	}
+	return sb.String()
+}
+
+func mark(c Component) string {
+	if c.Ready {
+		return "✓"
+	}
+	return "•"
+}
@@ -0,0 +1,131 @@
+package progress
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/decisiveai/mdai-cli/internal/kubehelper"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type Component struct {
+	Kind   string
+	Name   string
+	Ready  bool
+	Status string
+}
+
+func (c Component) String() string {
+	return c.Kind + "/" + c.Name
+}
+
+type Watcher struct {
+	helper    *kubehelper.Helper
+	namespace string
+	crds      []string
+}
+
+func NewWatcher(helper *kubehelper.Helper, namespace string, crds []string) *Watcher {
+	return &Watcher{helper: helper, namespace: namespace, crds: crds}
+}
+
+func (w *Watcher) Snapshot(ctx context.Context) ([]Component, error) {
+	components := make([]Component, 0, len(w.crds))
+	for _, name := range w.crds {
+		crd, err := w.helper.GetCRD(ctx, name)
+		if err != nil {
+			components = append(components, Component{Kind: "crd", Name: name, Status: "waiting to be created"})
+			continue
+		}
+		components = append(components, crdComponent(crd))
+	}
+
+	deployments, err := w.helper.ListDeployments(ctx, w.namespace)
+	if err != nil {
+		return components, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	pods, err := w.helper.ListPods(ctx, w.namespace)
+	if err != nil {
+		return components, fmt.Errorf("failed to list pods: %w", err)
+	}
+	slices.SortFunc(deployments.Items, func(a, b appsv1.Deployment) int { return strings.Compare(a.Name, b.Name) })
+	for _, d := range deployments.Items {
+		components = append(components, deploymentComponent(d, pods.Items))
+	}
+	return components, nil
+}
+
+func crdComponent(crd *apiextensionsv1.CustomResourceDefinition) Component {
+	component := Component{Kind: "crd", Name: crd.Name, Status: "waiting to be established"}
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+			component.Ready = true
+			component.Status = "established"
+		}
+	}
+	return component
+}
+
+func deploymentComponent(d appsv1.Deployment, pods []corev1.Pod) Component {
+	var replicas int32 = 1
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	component := Component{
+		Kind:   "deployment",
+		Name:   d.Name,
+		Status: fmt.Sprintf("%d/%d available", d.Status.AvailableReplicas, replicas),
+	}
+	for _, condition := range d.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable && condition.Status == corev1.ConditionTrue && d.Status.AvailableReplicas >= replicas {
+			component.Ready = true
+			return component
+		}
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+	if err != nil {
+		return component
+	}
+	for _, pod := range pods {
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if status := podStatus(pod); status != "" {
+			component.Status += ", " + status
+			break
+		}
+	}
+	return component
+}
+
+func podStatus(pod corev1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			return "unschedulable: " + condition.Message
+		}
+	}
+	for _, cs := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		if cs.State.Waiting == nil {
+			continue
+		}
+		switch reason := cs.State.Waiting.Reason; {
+		case reason == "ContainerCreating" && cs.ImageID == "":
+			return "pulling image " + cs.Image
+		case reason == "ContainerCreating", reason == "PodInitializing":
+			return "starting " + cs.Name
+		default:
+			return reason + " (" + cs.Name + ")"
+		}
+	}
+	if pod.Status.Phase == corev1.PodPending {
+		return "pod " + pod.Name + " pending"
+	}
+	return ""
+}
@@ -0,0 +1,338 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+const (
+	DefaultURL = "https://github.com/decisiveai/mdai-cli/releases/latest/download"
+	binaryName = "mdai"
+)
+
+// release.pub holds base64 ed25519 public keys, one per line.
+//
+//go:embed release.pub
+var releaseKeys []byte
+
+var ErrUnverified = errors.New("release could not be verified")
+
+var targets = map[string]string{
+	"darwin/amd64": "x86_64-apple-darwin",
+	"darwin/arm64": "aarch64-apple-darwin",
+	"linux/amd64":  "x86_64-unknown-linux-gnu",
+}
+
+type Release struct {
+	Version  string
+	Archive  string
+	Checksum string
+	Sha256   string
+	baseURL  string
+	manifest []byte
+}
+
+type distManifest struct {
+	Releases []struct {
+		AppName    string `json:"app_name"`
+		AppVersion string `json:"app_version"`
+	} `json:"releases"`
+	Artifacts map[string]struct {
+		Kind          string            `json:"kind"`
+		TargetTriples []string          `json:"target_triples"`
+		Checksum      string            `json:"checksum"`
+		Checksums     map[string]string `json:"checksums"`
+	} `json:"artifacts"`
+}
+
+func Target() (string, error) {
+	target, ok := targets[runtime.GOOS+"/"+runtime.GOARCH]
+	if !ok {
+		return "", fmt.Errorf("no mdai release is built for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+	return target, nil
+}
+
+func Latest(ctx context.Context, baseURL string) (*Release, error) {
+	target, err := Target()
+	if err != nil {
+		return nil, err
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	data, err := fetch(ctx, baseURL+"/dist-manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	var manifest distManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse release manifest: %w", err)
+	}
+
+	release := &Release{baseURL: baseURL, manifest: data}
+	for _, r := range manifest.Releases {
+		if r.AppName == binaryName {
+			release.Version = r.AppVersion
+		}
+	}
+	if release.Version == "" {
+		return nil, errors.New("release manifest has no mdai release")
+	}
+	for name, artifact := range manifest.Artifacts {
+		if artifact.Kind == "executable-zip" && strings.HasSuffix(name, ".tar.gz") && slices.Contains(artifact.TargetTriples, target) {
+			release.Archive = name
+			release.Checksum = artifact.Checksum
+			release.Sha256 = artifact.Checksums["sha256"]
+		}
+	}
+	if release.Archive == "" {
+		return nil, fmt.Errorf("release %s has no archive for %s", release.Version, target)
+	}
+	if release.Checksum == "" {
+		release.Checksum = release.Archive + ".sha256"
+	}
+	return release, nil
+}
+
+// Newer reports whether release is newer than current. Development builds
+// are never considered up to date.
+func (r *Release) Newer(current string) bool {
+	current, latest := "v"+strings.TrimPrefix(current, "v"), "v"+strings.TrimPrefix(r.Version, "v")
+	if !semver.IsValid(current) {
+		return true
+	}
+	return semver.Compare(latest, current) > 0
+}
+
+// Download checks the archive against the signed manifest sha256, or only the
+// published checksum without verification, and that the binary runs.
+func (r *Release) Download(ctx context.Context, insecureSkipVerify bool) (string, error) {
+	expected := r.Sha256
+	if !insecureSkipVerify {
+		if err := r.Verify(ctx); err != nil {
+			return "", err
+		}
+		if expected == "" {
+			return "", fmt.Errorf("%w: release manifest has no sha256 for %s", ErrUnverified, r.Archive)
+		}
+	}
+	archive, err := fetch(ctx, r.baseURL+"/"+r.Archive)
+	if err != nil {
+		return "", err
+	}
+	if expected == "" {
+		checksum, err := fetch(ctx, r.baseURL+"/"+r.Checksum)
+		if err != nil {
+			return "", err
+		}
+		fields := strings.Fields(string(checksum))
+		if len(fields) == 0 {
+			return "", fmt.Errorf("empty checksum file %s", r.Checksum)
+		}
+		expected = fields[0]
+	}
+	sum := sha256.Sum256(archive)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, expected) {
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", r.Archive, expected, got)
+	}
+
+	binary, err := extract(archive)
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.CommandContext(ctx, binary, "--version").Output()
+	if err != nil {
+		os.Remove(binary)
+		return "", fmt.Errorf("downloaded binary does not run: %w", err)
+	}
+	if !strings.Contains(string(out), strings.TrimPrefix(r.Version, "v")) {
+		os.Remove(binary)
+		return "", fmt.Errorf("downloaded binary reports %q, expected version %s", strings.TrimSpace(string(out)), r.Version)
+	}
+	return binary, nil
+}
+
+// Verify checks the signature of the release manifest against the keys
+// embedded in this build.
+func (r *Release) Verify(ctx context.Context) error {
+	signature, err := fetch(ctx, r.baseURL+"/dist-manifest.json.sig")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnverified, err)
+	}
+	return verifySignature(r.manifest, signature, releaseKeys)
+}
+
+// FetchSigned fetches url and checks it against the signature published as
+// url.sig.
+func FetchSigned(ctx context.Context, url string, keys []byte) ([]byte, error) {
+	data, err := fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := fetch(ctx, url+".sig")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnverified, err)
+	}
+	if err := verifySignature(data, signature, keys); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func ReleaseKeys() []byte {
+	return releaseKeys
+}
+
+func verifySignature(manifest, signature, keys []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("%w: invalid manifest signature: %w", ErrUnverified, err)
+	}
+	trusted, err := parseKeys(keys)
+	if err != nil {
+		return err
+	}
+	if len(trusted) == 0 {
+		return fmt.Errorf("%w: no release signing key in this build", ErrUnverified)
+	}
+	for _, key := range trusted {
+		if ed25519.Verify(key, manifest, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: manifest signature does not match any release key", ErrUnverified)
+}
+
+func parseKeys(keys []byte) ([]ed25519.PublicKey, error) {
+	var parsed []ed25519.PublicKey
+	for _, line := range strings.Split(string(keys), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(line)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid release key %q", line)
+		}
+		parsed = append(parsed, key)
+	}
+	return parsed, nil
+}
+
+// Replace atomically swaps the running executable for binary.
+func Replace(binary string) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to find mdai executable: %w", err)
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return "", fmt.Errorf("failed to find mdai executable: %w", err)
+	}
+	staged := filepath.Join(filepath.Dir(executable), "."+binaryName+".new")
+	if err := copyFile(binary, staged); err != nil {
+		return "", err
+	}
+	if err := os.Rename(staged, executable); err != nil {
+		os.Remove(staged)
+		return "", fmt.Errorf("failed to replace %s: %w", executable, err)
+	}
+	return executable, nil
+}
+
+func fetch(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute) //nolint: mnd
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
+
+func extract(archive []byte) (string, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return "", fmt.Errorf("failed to read archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("archive does not contain %s", binaryName)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || filepath.Base(header.Name) != binaryName {
+			continue
+		}
+		f, err := os.CreateTemp("", binaryName+"-")
+		if err != nil {
+			return "", fmt.Errorf("failed to extract %s: %w", binaryName, err)
+		}
+		if _, err := io.Copy(f, tr); err != nil { //nolint: gosec
+			f.Close()
+			return "", fmt.Errorf("failed to extract %s: %w", binaryName, err)
+		}
+		if err := f.Chmod(0o755); err != nil { //nolint: mnd
+			f.Close()
+			return "", fmt.Errorf("failed to extract %s: %w", binaryName, err)
+		}
+		if err := f.Close(); err != nil {
+			return "", fmt.Errorf("failed to extract %s: %w", binaryName, err)
+		}
+		return f.Name(), nil
+	}
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755) //nolint: mnd
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
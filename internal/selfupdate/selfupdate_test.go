@@ -0,0 +1,87 @@
+package selfupdate
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	other, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	manifest := []byte(`{"releases":[{"app_name":"mdai","app_version":"1.0.0"}]}`)
+	signature := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(private, manifest)) + "\n")
+	keys := func(keys ...ed25519.PublicKey) []byte {
+		data := "# release keys\n"
+		for _, key := range keys {
+			data += base64.StdEncoding.EncodeToString(key) + "\n"
+		}
+		return []byte(data)
+	}
+
+	tests := []struct {
+		name       string
+		manifest   []byte
+		keys       []byte
+		err        string
+		unverified bool
+	}{
+		{name: "signed by a trusted key", manifest: manifest, keys: keys(other, public)},
+		{name: "signed by another key", manifest: manifest, keys: keys(other), unverified: true},
+		{name: "tampered manifest", manifest: append([]byte(" "), manifest...), keys: keys(public), unverified: true},
+		{name: "no keys", manifest: manifest, keys: keys(), unverified: true},
+		{name: "invalid key", manifest: manifest, keys: []byte("c2hvcnQ=\n"), err: `invalid release key "c2hvcnQ="`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifySignature(tt.manifest, signature, tt.keys)
+			switch {
+			case tt.unverified:
+				require.ErrorIs(t, err, ErrUnverified)
+			case tt.err != "":
+				require.EqualError(t, err, tt.err)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReleaseKeys(t *testing.T) {
+	_, err := parseKeys(ReleaseKeys())
+	require.NoError(t, err)
+}
+
+func TestParseKeys(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	encoded := base64.StdEncoding.EncodeToString(public)
+
+	tests := []struct {
+		name string
+		keys string
+		want []ed25519.PublicKey
+		err  string
+	}{
+		{name: "key", keys: encoded + "\n", want: []ed25519.PublicKey{public}},
+		{name: "comments and blank lines", keys: "# release keys\n\n  " + encoded + "  \n", want: []ed25519.PublicKey{public}},
+		{name: "empty"},
+		{name: "short key", keys: "c2hvcnQ=\n", err: `invalid release key "c2hvcnQ="`},
+		{name: "not base64", keys: "not a key\n", err: `invalid release key "not a key"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := parseKeys([]byte(tt.keys))
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, keys)
+		})
+	}
+}
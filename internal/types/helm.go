@@ -8,6 +8,7 @@ type ChartSpec struct {
 	Namespace       string
 	Values          map[string]any
 	Version         string
+	Digest          string
 	CreateNamespace bool
 	Replace         bool
 	Wait            bool
@@ -16,4 +17,6 @@ type ChartSpec struct {
 	UpgradeCRDs     bool
 	Force           bool
 	Recreate        bool
+	Atomic          bool
+	CleanupOnFail   bool
 }
@@ -1,6 +1,15 @@
 package types
 
 type (
-	Kubeconfig  struct{}
-	Kubecontext struct{}
+	Kubeconfig         struct{}
+	Kubecontext        struct{}
+	Namespace          struct{}
+	Profile            struct{}
+	Engine             struct{}
+	Collector          struct{}
+	Output             struct{}
+	Timeout            struct{}
+	Retries            struct{}
+	ChartRepo          struct{}
+	InsecureSkipVerify struct{}
 )